@@ -0,0 +1,201 @@
+// cmd/audit.go - Audit commands for roles in an AWS profile
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// auditCmd groups the audit subcommands
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit IAM roles in an AWS profile",
+	Long: `Audit IAM roles for risky configuration before promoting them.
+
+Available audits:
+  trust    Report cross-account and wildcard trust exposure`,
+}
+
+// auditTrustCmd reports trust exposure for every role in a profile
+var auditTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Report cross-account and wildcard trust exposure",
+	Long: `Parse the trust policy of every role and report:
+- principals in accounts outside the profile's account and the known-accounts list
+- wildcard principals without conditions
+- third-party accounts that can assume without sts:ExternalId
+
+Examples:
+  iam-role-cloner audit trust --profile prod
+  iam-role-cloner audit trust -p prod --known-accounts 111111111111,222222222222
+  iam-role-cloner audit trust -p prod --output json --fail-on-findings`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, _ := cmd.Flags().GetString("profile")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		knownAccounts, _ := cmd.Flags().GetStringSlice("known-accounts")
+		output, _ := cmd.Flags().GetString("output")
+		failOnFindings, _ := cmd.Flags().GetBool("fail-on-findings")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if output != "table" && output != "json" {
+			fmt.Printf("❌ Error: unsupported output format '%s' (use 'table' or 'json')\n", output)
+			os.Exit(1)
+		}
+
+		findings, err := runAuditTrust(profile, pattern, knownAccounts, output, verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if failOnFindings && len(findings) > 0 {
+			os.Exit(2)
+		}
+	},
+}
+
+func runAuditTrust(profile, pattern string, knownAccounts []string, output string, verbose bool) ([]awsclient.TrustFinding, error) {
+	table := output == "table"
+
+	log, err := logger.New(verbose, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+	if !table {
+		log.SetOutput(os.Stderr)
+	}
+
+	if table {
+		log.Header(fmt.Sprintf("🔍 Trust Audit for Profile: %s", profile))
+	}
+
+	client, err := awsclient.NewClient(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	ctx := context.Background()
+	identity, err := client.ValidateCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate credentials: %v", err)
+	}
+	accountID := *identity.Account
+
+	if table {
+		log.Success(fmt.Sprintf("Connected to AWS Account: %s", accountID))
+		if len(knownAccounts) > 0 {
+			log.Info(fmt.Sprintf("Known accounts: %s", strings.Join(knownAccounts, ", ")))
+		}
+	}
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s.Suffix = " Fetching trust policies..."
+	if table {
+		s.Start()
+	}
+	roles, err := client.ListRoleSummaries(ctx, "")
+	s.Stop()
+	if err != nil {
+		return nil, err
+	}
+
+	if pattern != "" {
+		selector, err := awsclient.NewRoleSelector("", nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		selector.RequireNameContaining(pattern)
+		roles = selector.Filter(roles)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	findings := []awsclient.TrustFinding{}
+	for _, role := range roles {
+		trust, err := awsclient.ParseTrustPolicy(role.TrustPolicy)
+		if err != nil {
+			// A policy that cannot be read may hide any exposure
+			log.Warning(fmt.Sprintf("Could not parse the trust policy of %s: %v", role.Name, err))
+			findings = append(findings, awsclient.TrustFinding{
+				Role:     role.Name,
+				Severity: awsclient.SeverityHigh,
+				Check:    awsclient.CheckUnreadablePolicy,
+				Message:  fmt.Sprintf("trust policy could not be parsed: %v", err),
+			})
+			continue
+		}
+		findings = append(findings, awsclient.AuditTrust(role.Name, trust, accountID, knownAccounts)...)
+	}
+
+	// Most severe first, then by role name
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if awsclient.SeverityRank(a.Severity) != awsclient.SeverityRank(b.Severity) {
+			return awsclient.SeverityRank(a.Severity) > awsclient.SeverityRank(b.Severity)
+		}
+		return a.Role < b.Role
+	})
+
+	if !table {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return findings, encoder.Encode(findings)
+	}
+
+	log.Separator()
+	if len(findings) == 0 {
+		log.Success(fmt.Sprintf("No trust exposure found in %d roles", len(roles)))
+		return findings, nil
+	}
+
+	printTrustFindings(findings)
+
+	affected := make(map[string]bool)
+	for _, finding := range findings {
+		affected[finding.Role] = true
+	}
+
+	log.Separator()
+	log.Warning(fmt.Sprintf("%d findings in %d of %d roles", len(findings), len(affected), len(roles)))
+
+	return findings, nil
+}
+
+func printTrustFindings(findings []awsclient.TrustFinding) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tROLE\tCHECK\tPRINCIPAL\tDETAILS")
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			finding.Severity, finding.Role, finding.Check, finding.Principal, finding.Message)
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditTrustCmd)
+
+	// Required flags
+	auditTrustCmd.Flags().StringP("profile", "p", "", "AWS profile to use (required)")
+	auditTrustCmd.MarkFlagRequired("profile")
+
+	// Optional flags
+	auditTrustCmd.Flags().String("pattern", "", "Only audit roles whose name contains this pattern (case-insensitive)")
+	auditTrustCmd.Flags().StringSlice("known-accounts", nil, "Account IDs that are allowed to be trusted (comma-separated)")
+	auditTrustCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+	auditTrustCmd.Flags().Bool("fail-on-findings", false, "Exit with status 2 when any finding is reported")
+	auditTrustCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+}
@@ -4,17 +4,22 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
 	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/journal"
 	"iam-role-cloner/internal/logger"
+	"iam-role-cloner/internal/mapping"
+	"iam-role-cloner/internal/picker"
 )
 
 // Enhanced configuration struct
@@ -27,6 +32,90 @@ type CloneConfig struct {
 	Verbose       bool
 	DryRun        bool
 	LogFile       string
+
+	// Populated during profile validation
+	SourceAccount string
+	DestAccount   string
+
+	// Identity provider handling
+	CreateProviders  bool
+	MissingProviders []*awsclient.IdentityProvider
+
+	// EKS IRSA trust rewriting
+	IRSA              awsclient.IRSAMapping
+	UnmappedIRSARoles []string
+
+	// GitHub Actions OIDC trust rewriting
+	GitHub awsclient.GitHubMapping
+
+	// Privilege-escalation analysis
+	BlockSeverity      string
+	EscalationFindings []awsclient.EscalationFinding
+	ManagedPolicyDocs  map[string]string
+
+	// Post-transform leakage checks
+	StrictLeakage bool
+	LeakingRoles  []string
+
+	// IAM quota validation
+	Limits         awsclient.IAMLimits
+	OverLimitRoles []string
+
+	// Oversize inline policy handling
+	MinifyPolicies bool
+	OversizeInline string
+
+	// Identical inline policies shared as customer-managed policies
+	ShareInlinePolicies bool
+	SharedPolicies      map[string]*SharedPolicy
+
+	// Tag transformation rules
+	TagRules awsclient.TagRules
+
+	// Start of the run, used for clone dates
+	StartedAt time.Time
+	RunID     string
+
+	// Record of destination mutations, read back by undo
+	JournalFile string
+	Journal     *journal.Journal
+
+	// Flag values that shape the transformation, recorded in the journal
+	Options cloneOptions
+
+	// Provenance tags and description templates
+	Provenance        bool
+	DescriptionPrefix *template.Template
+	DescriptionSuffix *template.Template
+
+	// Role selector from --select, --include and --exclude
+	Selector *awsclient.RoleSelector
+	// Use the numbered prompt even on a terminal
+	NoTUI bool
+
+	// Explicit source to destination names from --mapping
+	Mapping *mapping.File
+
+	// Destination name collision handling
+	OnCollision string
+	// DestNames overrides destination names, e.g. after adding a suffix
+	DestNames map[string]string
+	// SyncTargets holds the current state of existing roles to update in place
+	SyncTargets map[string]*awsclient.RoleInfo
+
+	// Roles not used within this many days are left out of the batch
+	SkipUnusedDays int
+	SkippedUnused  []string
+
+	// Roles passed or assumed by the batch
+	DependencyMode string
+	Dependencies   []awsclient.RoleReference
+
+	// Transformed destination state, keyed by source role
+	Plans map[string]*RolePlan
+
+	// Role details fetched during pre-clone analysis
+	RoleInfos map[string]*awsclient.RoleInfo
 }
 
 // Enhanced cloneCmd with real AWS functionality
@@ -39,14 +128,25 @@ This command will:
 1. Validate AWS profiles and credentials
 2. Get pattern replacement rules (e.g., dev_ -> prod_)
 3. Select roles to clone (with auto-discovery)
-4. Clone roles with all policies and tags
-5. Apply pattern replacement to names and policy content
+4. Analyze trust policies and privilege-escalation risks
+5. Clone roles with all policies and tags
+6. Apply pattern replacement to names and policy content
 
 Examples:
   iam-role-cloner clone                                    # Interactive mode
   iam-role-cloner clone -s dev -d prod                     # With profiles
   iam-role-cloner clone --dry-run --verbose                # Dry run with details
-  iam-role-cloner clone --source-pattern "dev_" --dest-pattern "prod_"`,
+  iam-role-cloner clone --source-pattern "dev_" --dest-pattern "prod_"
+  iam-role-cloner clone -s dev -d prod --create-providers  # Recreate missing OIDC/SAML providers
+  iam-role-cloner clone -s dev -d prod --eks-oidc-map ABC123=DEF456 --irsa-namespace-map dev=prod
+  iam-role-cloner clone -s dev -d prod --github-env-map dev=prod --github-branch-map develop=main
+  iam-role-cloner clone -s dev -d prod --block-severity high  # Refuse roles with escalation paths
+  iam-role-cloner clone -s dev -d prod --minify-policies --oversize-inline managed
+  iam-role-cloner clone -s dev -d prod --share-inline-policies  # One managed policy per distinct inline document
+  iam-role-cloner clone -s dev -d prod --tag-set 'ClonedFrom={{.SourceRoleArn}}' --tag-delete CostCenter
+  iam-role-cloner clone -s dev -d prod --provenance --description-suffix ' (cloned {{.CloneDate}})'
+  iam-role-cloner clone -s dev -d prod --select 'tag:Team=payments and not lastused>90d'
+  iam-role-cloner clone -s legacy -d prod --mapping roles.yaml  # Explicit destination names`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get flags
@@ -54,24 +154,89 @@ Examples:
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		sourceProfile, _ := cmd.Flags().GetString("source-profile")
 		destProfile, _ := cmd.Flags().GetString("dest-profile")
-		sourcePattern, _ := cmd.Flags().GetString("source-pattern")
-		destPattern, _ := cmd.Flags().GetString("dest-pattern")
 		logFile, _ := cmd.Flags().GetString("log-file")
+		journalFile, _ := cmd.Flags().GetString("journal")
+		createProviders, _ := cmd.Flags().GetBool("create-providers")
+		blockSeverity, _ := cmd.Flags().GetString("block-severity")
+		strictLeakage, _ := cmd.Flags().GetBool("strict-leakage")
+		noTUI, _ := cmd.Flags().GetBool("no-tui")
+		onCollision, _ := cmd.Flags().GetString("on-collision")
+
+		switch onCollision {
+		case awsclient.CollisionFail, awsclient.CollisionSkip, awsclient.CollisionSuffix, awsclient.CollisionSync:
+		default:
+			fmt.Printf("❌ Error: invalid --on-collision '%s' (use fail, skip, suffix or sync)\n", onCollision)
+			os.Exit(1)
+		}
+
+		dependencyMode, _ := cmd.Flags().GetString("dependencies")
+		skipUnusedDays, _ := cmd.Flags().GetInt("skip-unused-days")
+
+		if skipUnusedDays < 0 {
+			fmt.Println("❌ Error: --skip-unused-days cannot be negative")
+			os.Exit(1)
+		}
+
+		switch dependencyMode {
+		case DependenciesAsk, DependenciesInclude, DependenciesIgnore:
+		default:
+			fmt.Printf("❌ Error: invalid --dependencies '%s' (use ask, include or ignore)\n", dependencyMode)
+			os.Exit(1)
+		}
+
+		selector, err := buildRoleSelector(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if blockSeverity != "" && awsclient.SeverityRank(blockSeverity) == 0 {
+			fmt.Printf("❌ Error: invalid --block-severity '%s' (use low, medium, high or critical)\n", blockSeverity)
+			os.Exit(1)
+		}
+
+		opts, err := readCloneOptions(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		startedAt := time.Now()
+		config, err := newCloneConfig(opts, startedAt)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		runID, err := newRunID(startedAt)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if journalFile == "" {
+			journalFile = fmt.Sprintf("iam-clone-%s.journal.jsonl", runID)
+		}
 
 		// Default log file name
 		if logFile == "" {
 			logFile = fmt.Sprintf("iam-clone-%s.log", time.Now().Format("20060102-150405"))
 		}
 
-		config := &CloneConfig{
-			SourceProfile: sourceProfile,
-			DestProfile:   destProfile,
-			SourcePattern: sourcePattern,
-			DestPattern:   destPattern,
-			Verbose:       verbose,
-			DryRun:        dryRun,
-			LogFile:       logFile,
-		}
+		config.SourceProfile = sourceProfile
+		config.DestProfile = destProfile
+		config.Verbose = verbose
+		config.DryRun = dryRun
+		config.LogFile = logFile
+		config.CreateProviders = createProviders
+		config.BlockSeverity = strings.ToUpper(blockSeverity)
+		config.StrictLeakage = strictLeakage
+		config.RunID = runID
+		config.JournalFile = journalFile
+		config.Selector = selector
+		config.NoTUI = noTUI
+		config.OnCollision = onCollision
+		config.DependencyMode = dependencyMode
+		config.SkipUnusedDays = skipUnusedDays
 
 		runEnhancedClone(config)
 	},
@@ -112,13 +277,19 @@ func runEnhancedClone(config *CloneConfig) {
 		os.Exit(1)
 	}
 
-	// Step 4: Show summary and confirm
+	// Step 4: Analyze selected roles before touching the destination
+	if err := analyzeRoles(config, log, reader); err != nil {
+		log.Error(fmt.Sprintf("Pre-clone analysis failed: %v", err))
+		os.Exit(1)
+	}
+
+	// Step 5: Show summary and confirm
 	if !showSummaryAndConfirm(config, log, reader) {
 		log.Info("Operation cancelled by user")
 		return
 	}
 
-	// Step 5: Perform the cloning
+	// Step 6: Perform the cloning
 	if err := performCloning(config, log); err != nil {
 		log.Error(fmt.Sprintf("Cloning failed: %v", err))
 		os.Exit(1)
@@ -126,6 +297,9 @@ func runEnhancedClone(config *CloneConfig) {
 
 	log.Success("🎉 Role cloning completed successfully!")
 	log.Info(fmt.Sprintf("Log file saved: %s", config.LogFile))
+	if config.Journal != nil {
+		log.Info(fmt.Sprintf("Journal saved: %s (undo with: iam-role-cloner undo %s)", config.JournalFile, config.RunID))
+	}
 }
 
 func getAndValidateProfiles(config *CloneConfig, log *logger.Logger, reader *bufio.Reader) error {
@@ -177,6 +351,9 @@ func getAndValidateProfiles(config *CloneConfig, log *logger.Logger, reader *buf
 	log.Success(fmt.Sprintf("Destination profile validated - Account: %s", *destIdentity.Account))
 	log.Debug(fmt.Sprintf("Destination ARN: %s", *destIdentity.Arn))
 
+	config.SourceAccount = *sourceIdentity.Account
+	config.DestAccount = *destIdentity.Account
+
 	if *sourceIdentity.Account == *destIdentity.Account {
 		log.Warning("Source and destination are the same AWS account")
 		fmt.Print("Continue anyway? (y/n): ")
@@ -193,6 +370,11 @@ func getPatternConfiguration(config *CloneConfig, log *logger.Logger, reader *bu
 	log.Info("Step 2: Pattern Configuration")
 	log.Separator()
 
+	if config.Mapping != nil && config.SourcePattern == "" && config.DestPattern == "" {
+		log.Info("No pattern replacement; destination names come from the mapping file")
+		return nil
+	}
+
 	if config.SourcePattern == "" {
 		fmt.Print("Enter source pattern (e.g., 'dev_', 'staging-'): ")
 		pattern, _ := reader.ReadString('\n')
@@ -219,6 +401,10 @@ func discoverAndSelectRoles(config *CloneConfig, log *logger.Logger, reader *buf
 	log.Info("Step 3: Role Discovery and Selection")
 	log.Separator()
 
+	if config.Mapping != nil {
+		return selectMappedRoles(config, log)
+	}
+
 	// Create source client for role discovery
 	sourceClient, err := awsclient.NewClient(config.SourceProfile)
 	if err != nil {
@@ -227,18 +413,25 @@ func discoverAndSelectRoles(config *CloneConfig, log *logger.Logger, reader *buf
 
 	ctx := context.Background()
 
-	// Discover roles with source pattern
+	// Discover roles with source pattern. Unlike --pattern of list, this is a
+	// case-sensitive prefix: the pattern is replaced in names as written, so a role
+	// matched otherwise would keep its source name.
 	log.Info("Discovering roles in source account...")
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
 	s.Start()
 
-	allRoles, err := sourceClient.ListRoles(ctx, config.SourcePattern)
+	allRoles, err := sourceClient.ListRoleSummaries(ctx, config.SourcePattern)
 	s.Stop()
 
 	if err != nil {
 		return fmt.Errorf("failed to discover roles: %v", err)
 	}
 
+	if !config.Selector.IsEmpty() {
+		log.Info("Applying role selector...")
+		allRoles = filterRoleSummaries(ctx, sourceClient, allRoles, config.Selector, log)
+	}
+
 	if len(allRoles) == 0 {
 		log.Warning(fmt.Sprintf("No roles found with pattern '%s'", config.SourcePattern))
 		return getRolesManually(config, log, reader)
@@ -246,23 +439,27 @@ func discoverAndSelectRoles(config *CloneConfig, log *logger.Logger, reader *buf
 
 	log.Success(fmt.Sprintf("Found %d roles with pattern '%s'", len(allRoles), config.SourcePattern))
 
+	if !config.NoTUI && picker.IsTerminal() {
+		return pickRoles(ctx, sourceClient, config, allRoles, log)
+	}
+
 	// Show discovered roles
 	fmt.Println("\nDiscovered roles:")
 	for i, role := range allRoles {
-		newRole := awsclient.GenerateNewRoleName(role, config.SourcePattern, config.DestPattern)
-		fmt.Printf("  %d. %s → %s\n", i+1, role, newRole)
+		newRole := destRoleName(config, role.Name)
+		fmt.Printf("  %d. %s → %s\n", i+1, role.Name, newRole)
 	}
 
 	// Let user select roles
-	fmt.Print("\nEnter role numbers to clone (e.g., 1,3,5 or 'all'): ")
+	fmt.Print("\nEnter role numbers to clone (e.g., 1,3,5-7), a selector (e.g., 'tag:Team=payments') or 'all': ")
 	selection, _ := reader.ReadString('\n')
 	selection = strings.TrimSpace(selection)
 
 	if strings.ToLower(selection) == "all" {
-		config.Roles = allRoles
+		config.Roles = roleSummaryNames(allRoles)
 		log.Success(fmt.Sprintf("Selected all %d roles", len(allRoles)))
 	} else {
-		selectedRoles, err := parseRoleSelection(selection, allRoles)
+		selectedRoles, err := parseRoleSelection(ctx, sourceClient, selection, allRoles, log)
 		if err != nil {
 			return fmt.Errorf("invalid selection: %v", err)
 		}
@@ -274,7 +471,7 @@ func discoverAndSelectRoles(config *CloneConfig, log *logger.Logger, reader *buf
 }
 
 func getRolesManually(config *CloneConfig, log *logger.Logger, reader *bufio.Reader) error {
-	log.Info("Manual role entry mode")
+	log.Info("Manual role entry mode (use --mapping for larger batches)")
 
 	fmt.Print("How many roles do you want to clone? (1-20): ")
 	countStr, _ := reader.ReadString('\n')
@@ -301,33 +498,377 @@ func getRolesManually(config *CloneConfig, log *logger.Logger, reader *bufio.Rea
 	return nil
 }
 
-func parseRoleSelection(selection string, allRoles []string) ([]string, error) {
-	parts := strings.Split(selection, ",")
-	var selectedRoles []string
+func analyzeRoles(config *CloneConfig, log *logger.Logger, reader *bufio.Reader) error {
+	log.Info("Step 4: Pre-clone Analysis")
+	log.Separator()
+
+	sourceClient, err := awsclient.NewClient(config.SourceProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create source client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	config.RoleInfos = make(map[string]*awsclient.RoleInfo)
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s.Suffix = " Fetching role details..."
+	s.Start()
 
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
+	for _, role := range config.Roles {
+		roleInfo, err := sourceClient.GetRoleInfo(ctx, role)
+		if err != nil {
+			// Leave it to the cloning step to report the failure for this role
+			log.Debug(fmt.Sprintf("Could not fetch %s for analysis: %v", role, err))
 			continue
 		}
+		config.RoleInfos[role] = roleInfo
+	}
+	s.Stop()
+
+	if config.SkipUnusedDays > 0 {
+		if err := skipUnusedRoles(config, log); err != nil {
+			return err
+		}
+	}
+
+	if err := resolveDependencies(ctx, sourceClient, config, log, reader); err != nil {
+		return err
+	}
 
-		index, err := strconv.Atoi(part)
+	if err := resolveCollisions(ctx, config, log); err != nil {
+		return err
+	}
+
+	checkIRSARoles(config, log)
+	checkGitHubTrust(config, log)
+	checkLeakage(config, log)
+	if config.ShareInlinePolicies {
+		planSharedPolicies(config, log)
+	}
+	checkLimits(config, log)
+
+	if err := checkIdentityProviders(ctx, sourceClient, config, log); err != nil {
+		return err
+	}
+
+	return checkPrivilegeEscalation(ctx, sourceClient, config, log)
+}
+
+// skipUnusedRoles leaves out roles not used within SkipUnusedDays, so dead roles
+// are not promoted. Roles created within that window are kept, since they have not
+// had the chance to be used, and so are roles whose details could not be fetched.
+func skipUnusedRoles(config *CloneConfig, log *logger.Logger) error {
+	cutoff := config.StartedAt.AddDate(0, 0, -config.SkipUnusedDays)
+	config.SkippedUnused = nil
+
+	var kept []string
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok || (roleInfo.LastUsed != nil && roleInfo.LastUsed.After(cutoff)) ||
+			(roleInfo.LastUsed == nil && roleInfo.CreateDate.After(cutoff)) {
+			kept = append(kept, role)
+			continue
+		}
+
+		config.SkippedUnused = append(config.SkippedUnused, role)
+		log.Warning(fmt.Sprintf("Skipping %s: last used %s, created %s", role,
+			formatLastUsed(roleInfo.LastUsed), roleInfo.CreateDate.Format("2006-01-02")))
+	}
+	config.Roles = kept
+
+	if len(config.SkippedUnused) == 0 {
+		log.Success(fmt.Sprintf("All roles used in the last %d days", config.SkipUnusedDays))
+		return nil
+	}
+	if len(config.Roles) == 0 {
+		return fmt.Errorf("none of the selected roles was used in the last %d days", config.SkipUnusedDays)
+	}
+	log.Info(fmt.Sprintf("Skipped %d role(s) not used in the last %d days, %d left to clone",
+		len(config.SkippedUnused), config.SkipUnusedDays, len(config.Roles)))
+
+	return nil
+}
+
+// checkPrivilegeEscalation runs the escalation rule catalog over the policies
+// each role will have in the destination, including those a mapping file adds,
+// and blocks the clone above the configured severity
+func checkPrivilegeEscalation(ctx context.Context, sourceClient *awsclient.Client, config *CloneConfig, log *logger.Logger) error {
+	if config.ManagedPolicyDocs == nil {
+		config.ManagedPolicyDocs = make(map[string]string)
+	}
+	config.EscalationFindings = nil
+
+	// Policies a mapping file attaches may live in the destination account
+	destClient := sourceClient
+	if config.DestAccount != config.SourceAccount {
+		var err error
+		destClient, err = awsclient.NewClient(config.DestProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create destination client: %v", err)
+		}
+	}
+
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			continue
+		}
+
+		var documents map[string]string
+		if plan, ok := config.Plans[role]; ok {
+			documents = planPolicyDocuments(ctx, sourceClient, destClient, plan, config, log)
+		} else {
+			documents = rolePolicyDocuments(ctx, sourceClient, config.ManagedPolicyDocs, roleInfo, log)
+		}
+
+		findings, err := awsclient.AnalyzeEscalation(role, documents)
 		if err != nil {
-			return nil, fmt.Errorf("invalid number: %s", part)
+			log.Warning(fmt.Sprintf("Could not analyze policies of %s: %v", role, err))
+			continue
+		}
+		config.EscalationFindings = append(config.EscalationFindings, findings...)
+	}
+
+	if len(config.EscalationFindings) == 0 {
+		log.Success("No privilege-escalation paths found")
+		return nil
+	}
+
+	var blocked []string
+	for _, finding := range config.EscalationFindings {
+		message := fmt.Sprintf("Escalation path in %s: %s", finding.Role, finding)
+		if awsclient.SeverityRank(finding.Severity) >= awsclient.SeverityRank(awsclient.SeverityHigh) {
+			log.Warning(message)
+		} else {
+			log.Info(message)
+		}
+
+		if config.BlockSeverity != "" &&
+			awsclient.SeverityRank(finding.Severity) >= awsclient.SeverityRank(config.BlockSeverity) {
+			blocked = append(blocked, finding.Role)
+		}
+	}
+
+	if len(blocked) > 0 {
+		return fmt.Errorf("roles with escalation findings at or above %s: %s",
+			config.BlockSeverity, strings.Join(awsclient.Dedupe(blocked), ", "))
+	}
+
+	return nil
+}
+
+// planPolicyDocuments returns the documents a role will carry in the destination:
+// inline, converted and shared policies keyed by name, and managed policies keyed
+// by ARN. Managed documents are read from the account that owns the policy and
+// kept in config.ManagedPolicyDocs; policies that cannot be read are skipped with
+// a warning.
+func planPolicyDocuments(ctx context.Context, sourceClient, destClient *awsclient.Client, plan *RolePlan,
+	config *CloneConfig, log *logger.Logger) map[string]string {
+
+	documents := make(map[string]string)
+	for name, document := range plan.InlinePolicies {
+		documents[name] = document
+	}
+	for _, converted := range plan.ConvertedPolicies {
+		documents[converted.Name] = converted.Document
+	}
+	for _, name := range plan.SharedPolicies {
+		if shared, ok := config.SharedPolicies[name]; ok {
+			documents[name] = shared.Document
+		}
+	}
+
+	for _, policyArn := range plan.ManagedPolicies {
+		document, cached := config.ManagedPolicyDocs[policyArn]
+		if !cached {
+			client := sourceClient
+			if parts := strings.SplitN(policyArn, ":", 6); len(parts) == 6 && parts[4] == config.DestAccount {
+				client = destClient
+			}
+
+			var err error
+			document, err = client.GetManagedPolicyDocument(ctx, policyArn)
+			if err != nil {
+				log.Warning(fmt.Sprintf("Could not fetch %s for analysis: %v", policyArn, err))
+				continue
+			}
+			config.ManagedPolicyDocs[policyArn] = document
+		}
+		documents[policyArn] = document
+	}
+
+	return documents
+}
+
+// checkIRSARoles flags EKS service account roles whose cluster has no OIDC mapping
+func checkIRSARoles(config *CloneConfig, log *logger.Logger) {
+	config.UnmappedIRSARoles = nil
+
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			continue
 		}
 
-		if index < 1 || index > len(allRoles) {
-			return nil, fmt.Errorf("number out of range: %d", index)
+		providers, err := awsclient.FindEKSProviders(roleInfo.TrustPolicy)
+		if err != nil || len(providers) == 0 {
+			continue
 		}
 
-		selectedRoles = append(selectedRoles, allRoles[index-1])
+		for _, provider := range providers {
+			if dest, mapped := config.IRSA.MapProvider(provider); mapped {
+				log.Debug(fmt.Sprintf("IRSA role %s: %s → %s", role, provider, dest))
+				continue
+			}
+
+			log.Warning(fmt.Sprintf("IRSA role %s trusts unmapped EKS cluster %s", role, provider))
+			config.UnmappedIRSARoles = append(config.UnmappedIRSARoles, role)
+			break
+		}
+	}
+
+	if len(config.UnmappedIRSARoles) > 0 {
+		log.Warning("Unmapped IRSA roles will keep trusting the source cluster. Use --eks-oidc-map to map cluster OIDC IDs")
 	}
+}
+
+// checkGitHubTrust reports GitHub Actions trust conditions that need attention
+func checkGitHubTrust(config *CloneConfig, log *logger.Logger) {
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			continue
+		}
+
+		_, warnings, err := transformTrustPolicy(roleInfo.TrustPolicy, config)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not transform trust policy of %s: %v", role, err))
+			continue
+		}
 
-	return selectedRoles, nil
+		for _, warning := range warnings {
+			log.Warning(fmt.Sprintf("%s: %s", role, warning))
+		}
+	}
+}
+
+// checkIdentityProviders finds federated principals and verifies they exist in the destination
+func checkIdentityProviders(ctx context.Context, sourceClient *awsclient.Client, config *CloneConfig, log *logger.Logger) error {
+	// Keyed by destination ARN, since a mapping row can rename a provider for one role only
+	referencedBy := make(map[string][]string)
+	sourceArns := make(map[string]string)
+	clusterMapped := make(map[string]bool)
+	var destArns []string
+
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			continue
+		}
+
+		providers, err := awsclient.FindFederatedProviders(roleInfo.TrustPolicy)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not analyze trust policy of %s: %v", role, err))
+			continue
+		}
+
+		for _, arn := range providers {
+			destArn, mapped := mapProviderArn(arn, role, config)
+			if _, seen := referencedBy[destArn]; !seen {
+				destArns = append(destArns, destArn)
+				sourceArns[destArn] = arn
+				clusterMapped[destArn] = mapped
+			}
+			referencedBy[destArn] = append(referencedBy[destArn], role)
+		}
+	}
+
+	if len(destArns) == 0 {
+		log.Debug("No federated identity providers referenced by trust policies")
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("Trust policies reference %d identity provider(s)", len(destArns)))
+
+	destClient, err := awsclient.NewClient(config.DestProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create destination client: %v", err)
+	}
+
+	config.MissingProviders = nil
+	for _, destArn := range destArns {
+		arn := sourceArns[destArn]
+		if destArn != arn {
+			log.Debug(fmt.Sprintf("  %s → %s", arn, destArn))
+		}
+
+		exists, err := destClient.IdentityProviderExists(ctx, destArn)
+		if err != nil {
+			return fmt.Errorf("failed to check provider in destination: %v", err)
+		}
+		if exists {
+			log.Success(fmt.Sprintf("  Provider exists in destination: %s", destArn))
+			continue
+		}
+
+		// A mapped EKS cluster has its own provider that cannot be copied from the source
+		if clusterMapped[destArn] {
+			log.Warning(fmt.Sprintf("  Mapped EKS provider missing in destination: %s (used by %s)",
+				destArn, strings.Join(referencedBy[destArn], ", ")))
+			continue
+		}
+
+		provider, err := sourceClient.GetIdentityProvider(ctx, arn)
+		if err != nil {
+			log.Warning(fmt.Sprintf("  Provider %s is missing in destination and could not be read from source: %v", destArn, err))
+			continue
+		}
+
+		// Create it under the name the transformed trust policies use
+		if _, _, destName, ok := awsclient.ParseProviderArn(destArn); ok && destName != provider.Name {
+			provider.Name = destName
+			provider.URL = ""
+		}
+		provider.Arn = destArn
+
+		config.MissingProviders = append(config.MissingProviders, provider)
+		if config.CreateProviders {
+			log.Info(fmt.Sprintf("  Provider will be created in destination: %s (used by %s)",
+				destArn, strings.Join(referencedBy[destArn], ", ")))
+		} else {
+			log.Warning(fmt.Sprintf("  Provider missing in destination: %s (used by %s)",
+				destArn, strings.Join(referencedBy[destArn], ", ")))
+		}
+	}
+
+	if len(config.MissingProviders) > 0 && !config.CreateProviders {
+		log.Warning("Roles trusting missing providers will fail to assume. Use --create-providers to recreate them")
+	}
+
+	return nil
+}
+
+// createMissingProviders recreates the identity providers found missing during analysis
+func createMissingProviders(ctx context.Context, destClient *awsclient.Client, config *CloneConfig, log *logger.Logger) {
+	for _, provider := range config.MissingProviders {
+		if config.DryRun {
+			log.Info(fmt.Sprintf("  [DRY RUN] Would create %s: %s", provider.Type, provider.Name))
+			continue
+		}
+
+		arn, err := destClient.CreateIdentityProvider(ctx, provider)
+		if err != nil {
+			log.Warning(fmt.Sprintf("  Failed to create identity provider %s: %v", provider.Name, err))
+			continue
+		}
+		recordMutation(config, log, journal.Entry{Action: journal.ActionCreateProvider, ProviderArn: arn})
+		log.Success(fmt.Sprintf("  Created identity provider: %s", arn))
+	}
 }
 
 func showSummaryAndConfirm(config *CloneConfig, log *logger.Logger, reader *bufio.Reader) bool {
-	log.Info("Step 4: Configuration Summary")
+	log.Info("Step 5: Configuration Summary")
 	log.Separator()
 
 	fmt.Printf("Source Profile:      %s\n", config.SourceProfile)
@@ -336,11 +877,39 @@ func showSummaryAndConfirm(config *CloneConfig, log *logger.Logger, reader *bufi
 	fmt.Printf("Dry Run:            %v\n", config.DryRun)
 	fmt.Printf("Verbose Logging:    %v\n", config.Verbose)
 	fmt.Printf("Log File:           %s\n", config.LogFile)
-	fmt.Println("\nRoles to clone:")
+	if len(config.MissingProviders) > 0 {
+		fmt.Printf("Missing Providers:  %d (create: %v)\n", len(config.MissingProviders), config.CreateProviders)
+	}
+	if len(config.UnmappedIRSARoles) > 0 {
+		fmt.Printf("Unmapped IRSA:      %s\n", strings.Join(config.UnmappedIRSARoles, ", "))
+	}
+	if len(config.EscalationFindings) > 0 {
+		fmt.Printf("Escalation Paths:   %d finding(s)\n", len(config.EscalationFindings))
+	}
+	if len(config.LeakingRoles) > 0 {
+		fmt.Printf("Source Leakage:     %d role(s) (strict: %v)\n", len(config.LeakingRoles), config.StrictLeakage)
+	}
+	if len(config.OverLimitRoles) > 0 {
+		fmt.Printf("Over IAM Limits:    %s\n", strings.Join(config.OverLimitRoles, ", "))
+	}
+	if len(config.SharedPolicies) > 0 {
+		fmt.Printf("Shared Policies:    %d\n", len(config.SharedPolicies))
+	}
+	if len(config.SyncTargets) > 0 {
+		fmt.Printf("Update in Place:    %d existing role(s)\n", len(config.SyncTargets))
+	}
+	if len(config.SkippedUnused) > 0 {
+		fmt.Printf("Skipped Unused:     %s (not used in %d days)\n", strings.Join(config.SkippedUnused, ", "), config.SkipUnusedDays)
+	}
+	if len(config.Dependencies) > 0 {
+		showDependencyGraph(config)
+	} else {
+		fmt.Println("\nRoles to clone:")
 
-	for i, role := range config.Roles {
-		newRole := awsclient.GenerateNewRoleName(role, config.SourcePattern, config.DestPattern)
-		fmt.Printf("  %d. %s → %s\n", i+1, role, newRole)
+		for i, role := range config.Roles {
+			newRole := destRoleName(config, role)
+			fmt.Printf("  %d. %s → %s\n", i+1, role, newRole)
+		}
 	}
 
 	fmt.Print("\nProceed with cloning? (y/n): ")
@@ -351,7 +920,7 @@ func showSummaryAndConfirm(config *CloneConfig, log *logger.Logger, reader *bufi
 }
 
 func performCloning(config *CloneConfig, log *logger.Logger) error {
-	log.Info("Step 5: Role Cloning Process")
+	log.Info("Step 6: Role Cloning Process")
 	log.Separator()
 
 	// Create AWS clients
@@ -368,11 +937,37 @@ func performCloning(config *CloneConfig, log *logger.Logger) error {
 		}
 	}
 
+	if !config.DryRun {
+		// Patterns may have been entered interactively
+		config.Options.SourcePattern = config.SourcePattern
+		config.Options.DestPattern = config.DestPattern
+		options, err := config.Options.encode()
+		if err != nil {
+			return err
+		}
+		config.Journal, err = journal.Create(config.JournalFile, config.RunID, config.DestProfile, config.DestAccount, options)
+		if err != nil {
+			return err
+		}
+		defer config.Journal.Close()
+		log.Info(fmt.Sprintf("Recording changes in journal: %s (run %s)", config.JournalFile, config.RunID))
+	}
+
 	ctx := context.Background()
 	successCount := 0
 
+	if config.CreateProviders && len(config.MissingProviders) > 0 {
+		log.Info(fmt.Sprintf("Creating %d missing identity provider(s)...", len(config.MissingProviders)))
+		createMissingProviders(ctx, destClient, config, log)
+	}
+
+	if len(config.SharedPolicies) > 0 {
+		log.Info(fmt.Sprintf("Creating %d shared managed policies...", len(config.SharedPolicies)))
+		createSharedPolicies(ctx, destClient, config, log)
+	}
+
 	for i, role := range config.Roles {
-		newRole := awsclient.GenerateNewRoleName(role, config.SourcePattern, config.DestPattern)
+		newRole := destRoleName(config, role)
 		log.Progress(i+1, len(config.Roles), fmt.Sprintf("Cloning: %s → %s", role, newRole))
 
 		if err := cloneSingleRole(ctx, sourceClient, destClient, role, newRole, config, log); err != nil {
@@ -397,109 +992,215 @@ func performCloning(config *CloneConfig, log *logger.Logger) error {
 func cloneSingleRole(ctx context.Context, sourceClient, destClient *awsclient.Client,
 	sourceRole, destRole string, config *CloneConfig, log *logger.Logger) error {
 
-	// Step 1: Get role information
-	log.Debug(fmt.Sprintf("  Getting role information for: %s", sourceRole))
-	roleInfo, err := sourceClient.GetRoleInfo(ctx, sourceRole)
-	if err != nil {
-		return fmt.Errorf("failed to get role info: %v", err)
+	// Step 1: Get role information (reuse what the analysis step fetched)
+	roleInfo, ok := config.RoleInfos[sourceRole]
+	if !ok {
+		log.Debug(fmt.Sprintf("  Getting role information for: %s", sourceRole))
+		var err error
+		roleInfo, err = sourceClient.GetRoleInfo(ctx, sourceRole)
+		if err != nil {
+			return fmt.Errorf("failed to get role info: %v", err)
+		}
 	}
 
 	log.Debug(fmt.Sprintf("  Retrieved role info: %d managed policies, %d inline policies, %d tags",
 		len(roleInfo.ManagedPolicies), len(roleInfo.InlinePolicies), len(roleInfo.Tags)))
 
+	// Reuse the plan built during analysis, or build it now
+	plan, ok := config.Plans[sourceRole]
+	if !ok {
+		var err error
+		plan, err = buildRolePlan(roleInfo, destRole, config)
+		if err != nil {
+			return err
+		}
+		if err := scanForLeaks(plan, config); err != nil {
+			return err
+		}
+		validatePlan(plan, config)
+	}
+
+	if len(plan.Violations) > 0 {
+		return fmt.Errorf("exceeds IAM limits: %s", plan.Violations[0])
+	}
+
+	if config.StrictLeakage && len(plan.Leaks) > 0 {
+		return fmt.Errorf("%d leftover source reference(s) after transformation (strict mode)", len(plan.Leaks))
+	}
+
+	if current, ok := config.SyncTargets[sourceRole]; ok && config.DryRun {
+		changes, err := describeSync(plan, current, config)
+		if err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("  [DRY RUN] Would update existing role %s in place (%d change(s))", destRole, len(changes)))
+		for _, change := range changes {
+			log.Info(fmt.Sprintf("    - %s", change))
+		}
+		return nil
+	}
+
 	if config.DryRun {
 		log.Info("  [DRY RUN] Would create role and copy policies/tags")
+		for _, ref := range roleDependencies(config, sourceRole) {
+			log.Info(fmt.Sprintf("  [DRY RUN] Depends on %s (%s), cloned earlier in this run", destRoleName(config, ref.ToRole), ref.Action))
+		}
 
-		// Process the trust policy to show what would actually be sent to AWS
-		processedTrustPolicy := awsclient.ReplacePatternInJSON(
-			roleInfo.TrustPolicy, config.SourcePattern, config.DestPattern)
+		// Show the trust policy that would actually be sent to AWS
+		processedTrustPolicy := plan.TrustPolicy
 
 		if config.Verbose {
 			log.Debug(fmt.Sprintf("  [DRY RUN] Original trust policy: %s", roleInfo.TrustPolicy))
 			log.Debug(fmt.Sprintf("  [DRY RUN] Processed trust policy: %s", processedTrustPolicy))
 
 			// Show managed policies that would be attached
-			if len(roleInfo.ManagedPolicies) > 0 {
-				log.Debug(fmt.Sprintf("  [DRY RUN] Would attach %d managed policies:", len(roleInfo.ManagedPolicies)))
-				for _, policy := range roleInfo.ManagedPolicies {
+			if len(plan.ManagedPolicies) > 0 {
+				log.Debug(fmt.Sprintf("  [DRY RUN] Would attach %d managed policies:", len(plan.ManagedPolicies)))
+				for _, policy := range plan.ManagedPolicies {
 					log.Debug(fmt.Sprintf("    - %s", policy))
 				}
 			}
 
 			// Show inline policies that would be created
-			if len(roleInfo.InlinePolicies) > 0 {
-				log.Debug(fmt.Sprintf("  [DRY RUN] Would create %d inline policies:", len(roleInfo.InlinePolicies)))
-				for policyName := range roleInfo.InlinePolicies {
-					newPolicyName := awsclient.GenerateNewRoleName(policyName, config.SourcePattern, config.DestPattern)
-					log.Debug(fmt.Sprintf("    - %s → %s", policyName, newPolicyName))
+			if len(plan.InlinePolicies) > 0 {
+				log.Debug(fmt.Sprintf("  [DRY RUN] Would create %d inline policies:", len(plan.InlinePolicies)))
+				for _, newPolicyName := range plan.InlinePolicyNames() {
+					log.Debug(fmt.Sprintf("    - %s → %s", plan.InlineSources[newPolicyName], newPolicyName))
 				}
 			}
 
-			// Show tags that would be copied
-			if len(roleInfo.Tags) > 0 {
-				log.Debug(fmt.Sprintf("  [DRY RUN] Would copy %d tags:", len(roleInfo.Tags)))
-				for key, value := range roleInfo.Tags {
-					if key == "Environment" {
-						envValue := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(
-							config.DestPattern, "_"), "-"), ".")
-						log.Debug(fmt.Sprintf("    - %s: %s → %s", key, value, envValue))
-					} else {
-						newValue := awsclient.ReplacePatternInJSON(value, config.SourcePattern, config.DestPattern)
-						if newValue != value {
-							log.Debug(fmt.Sprintf("    - %s: %s → %s", key, value, newValue))
-						} else {
-							log.Debug(fmt.Sprintf("    - %s: %s", key, value))
-						}
-					}
+			// Show inline policies that would become customer-managed policies
+			if len(plan.ConvertedPolicies) > 0 {
+				log.Debug(fmt.Sprintf("  [DRY RUN] Would create %d customer-managed policies:", len(plan.ConvertedPolicies)))
+				for _, converted := range plan.ConvertedPolicies {
+					log.Debug(fmt.Sprintf("    - %s → %s", converted.Source, converted.Name))
+				}
+			}
+
+			// Show shared policies that would be attached
+			if len(plan.SharedPolicies) > 0 {
+				log.Debug(fmt.Sprintf("  [DRY RUN] Would attach %d shared policies:", len(plan.SharedPolicies)))
+				for _, name := range plan.SharedPolicies {
+					log.Debug(fmt.Sprintf("    - %s", name))
+				}
+			}
+
+			if plan.Description != "" {
+				log.Debug(fmt.Sprintf("  [DRY RUN] Description: %s", plan.Description))
+			}
+
+			// Show tags that would be applied
+			if len(plan.Tags) > 0 || len(roleInfo.Tags) > 0 {
+				log.Debug(fmt.Sprintf("  [DRY RUN] Would apply %d tags:", len(plan.Tags)))
+				for _, line := range describeTagChanges(roleInfo.Tags, plan.Tags) {
+					log.Debug(fmt.Sprintf("    - %s", line))
 				}
 			}
 		}
 		return nil
 	}
 
-	// Check if destination role already exists
+	// Step 2: Create the role, or update it in place when it is synced
+	var synced *awsclient.RoleInfo
 	if destClient.RoleExists(ctx, destRole) {
-		return fmt.Errorf("destination role already exists: %s", destRole)
-	}
-
-	// Step 2: Create the role with pattern-replaced trust policy
-	log.Debug("  Creating new role...")
-	processedTrustPolicy := awsclient.ReplacePatternInJSON(
-		roleInfo.TrustPolicy, config.SourcePattern, config.DestPattern)
-
-	// Debug: Show the processed trust policy if verbose
-	if config.Verbose {
-		log.Debug(fmt.Sprintf("  Original trust policy: %s", roleInfo.TrustPolicy))
-		log.Debug(fmt.Sprintf("  Processed trust policy: %s", processedTrustPolicy))
-	}
+		if _, ok := config.SyncTargets[sourceRole]; !ok {
+			return fmt.Errorf("destination role already exists: %s", destRole)
+		}
 
-	description := fmt.Sprintf("Cloned from %s on %s", sourceRole, time.Now().Format("2006-01-02 15:04:05"))
-	if err := destClient.CreateRole(ctx, destRole, processedTrustPolicy, description); err != nil {
-		// Enhanced error message with policy content
-		if config.Verbose {
-			log.Error(fmt.Sprintf("  Failed trust policy content: %s", processedTrustPolicy))
+		log.Debug("  Updating existing role in place...")
+		var err error
+		synced, err = prepareSync(ctx, destClient, plan, config, log)
+		if err != nil {
+			return err
 		}
-		return fmt.Errorf("failed to create role: %v", err)
+	} else if err := createDestRole(ctx, destClient, roleInfo, plan, config, log); err != nil {
+		return err
 	}
 
-	log.Debug("  Role created successfully")
+	// Policies the role should end up with, for pruning a synced role
+	wantedManaged := make(map[string]bool)
+	wantedInline := make(map[string]bool)
 
 	// Step 3: Attach managed policies
-	log.Debug(fmt.Sprintf("  Attaching %d managed policies...", len(roleInfo.ManagedPolicies)))
-	for _, policyArn := range roleInfo.ManagedPolicies {
+	log.Debug(fmt.Sprintf("  Attaching %d managed policies...", len(plan.ManagedPolicies)))
+	for _, policyArn := range plan.ManagedPolicies {
+		wantedManaged[policyArn] = true
 		if err := destClient.AttachManagedPolicy(ctx, destRole, policyArn); err != nil {
 			log.Warning(fmt.Sprintf("    Failed to attach managed policy %s: %v", policyArn, err))
 		} else {
+			recordMutation(config, log, journal.Entry{Action: journal.ActionAttachPolicy, Role: destRole, PolicyArn: policyArn})
 			log.Debug(fmt.Sprintf("    Attached: %s", policyArn))
 		}
 	}
 
+	// Oversize inline policies are created as customer-managed policies instead
+	for _, converted := range plan.ConvertedPolicies {
+		// A synced role may already have this policy from an earlier run
+		wantedManaged[customerPolicyArn(config.DestAccount, converted.Name)] = true
+
+		description := fmt.Sprintf("Inline policy %s of %s", converted.Source, destRole)
+		policyArn, err := destClient.CreateManagedPolicy(ctx, converted.Name, converted.Document, description)
+		switch {
+		case err == nil:
+			recordMutation(config, log, journal.Entry{
+				Action: journal.ActionCreatePolicy, PolicyArn: policyArn, PolicyName: converted.Name,
+				Hash: documentHash(converted.Document),
+			})
+		case synced != nil && awsclient.IsEntityAlreadyExists(err):
+			// An earlier run created the policy; bring its document up to date
+			policyArn = customerPolicyArn(config.DestAccount, converted.Name)
+			updated, err := destClient.UpdateManagedPolicy(ctx, policyArn, converted.Document)
+			if err != nil {
+				return fmt.Errorf("failed to update managed policy %s: %v", converted.Name, err)
+			}
+			if updated {
+				recordMutation(config, log, journal.Entry{
+					Action: journal.ActionUpdatePolicy, PolicyArn: policyArn, PolicyName: converted.Name,
+					Hash: documentHash(converted.Document),
+				})
+				log.Debug(fmt.Sprintf("    Updated managed policy: %s", policyArn))
+			}
+		default:
+			log.Warning(fmt.Sprintf("    Failed to create managed policy %s: %v", converted.Name, err))
+			continue
+		}
+		if err := destClient.AttachManagedPolicy(ctx, destRole, policyArn); err != nil {
+			log.Warning(fmt.Sprintf("    Failed to attach managed policy %s: %v", policyArn, err))
+		} else {
+			recordMutation(config, log, journal.Entry{Action: journal.ActionAttachPolicy, Role: destRole, PolicyArn: policyArn})
+			log.Debug(fmt.Sprintf("    Created and attached: %s", policyArn))
+		}
+	}
+
+	// Shared policies were created once for the batch; fall back to inline if that failed
+	for _, name := range plan.SharedPolicies {
+		shared := config.SharedPolicies[name]
+		if shared.Arn == "" {
+			wantedInline[name] = true
+			if err := destClient.CreateInlinePolicy(ctx, destRole, name, shared.Document); err != nil {
+				log.Warning(fmt.Sprintf("    Failed to create inline policy %s: %v", name, err))
+			} else {
+				recordMutation(config, log, journal.Entry{
+					Action: journal.ActionPutRolePolicy, Role: destRole, PolicyName: name, Hash: shared.Hash,
+				})
+				log.Debug(fmt.Sprintf("    Created inline policy: %s", name))
+			}
+			continue
+		}
+		wantedManaged[shared.Arn] = true
+		if err := destClient.AttachManagedPolicy(ctx, destRole, shared.Arn); err != nil {
+			log.Warning(fmt.Sprintf("    Failed to attach shared policy %s: %v", shared.Arn, err))
+		} else {
+			recordMutation(config, log, journal.Entry{Action: journal.ActionAttachPolicy, Role: destRole, PolicyArn: shared.Arn})
+			log.Debug(fmt.Sprintf("    Attached shared policy: %s", shared.Arn))
+		}
+	}
+
 	// Step 4: Create inline policies with pattern replacement
-	log.Debug(fmt.Sprintf("  Creating %d inline policies...", len(roleInfo.InlinePolicies)))
-	for policyName, policyDocument := range roleInfo.InlinePolicies {
-		newPolicyName := awsclient.GenerateNewRoleName(policyName, config.SourcePattern, config.DestPattern)
-		processedDocument := awsclient.ReplacePatternInJSON(
-			policyDocument, config.SourcePattern, config.DestPattern)
+	log.Debug(fmt.Sprintf("  Creating %d inline policies...", len(plan.InlinePolicies)))
+	for _, newPolicyName := range plan.InlinePolicyNames() {
+		processedDocument := plan.InlinePolicies[newPolicyName]
+		wantedInline[newPolicyName] = true
 
 		if config.Verbose {
 			log.Debug(fmt.Sprintf("    Creating inline policy: %s", newPolicyName))
@@ -509,26 +1210,18 @@ func cloneSingleRole(ctx context.Context, sourceClient, destClient *awsclient.Cl
 		if err := destClient.CreateInlinePolicy(ctx, destRole, newPolicyName, processedDocument); err != nil {
 			log.Warning(fmt.Sprintf("    Failed to create inline policy %s: %v", newPolicyName, err))
 		} else {
+			recordMutation(config, log, journal.Entry{
+				Action: journal.ActionPutRolePolicy, Role: destRole, PolicyName: newPolicyName,
+				Hash: documentHash(processedDocument),
+			})
 			log.Debug(fmt.Sprintf("    Created inline policy: %s", newPolicyName))
 		}
 	}
 
 	// Step 5: Copy and update tags
-	if len(roleInfo.Tags) > 0 {
-		log.Debug(fmt.Sprintf("  Copying %d tags...", len(roleInfo.Tags)))
-		processedTags := make(map[string]string)
-
-		for key, value := range roleInfo.Tags {
-			// Replace patterns in tag values and update Environment tag
-			if key == "Environment" {
-				// Set environment to destination pattern (cleaned)
-				envValue := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(
-					config.DestPattern, "_"), "-"), ".")
-				processedTags[key] = envValue
-			} else {
-				processedTags[key] = awsclient.ReplacePatternInJSON(value, config.SourcePattern, config.DestPattern)
-			}
-		}
+	if len(plan.Tags) > 0 {
+		log.Debug(fmt.Sprintf("  Copying %d tags...", len(plan.Tags)))
+		processedTags := plan.Tags
 
 		if config.Verbose {
 			log.Debug(fmt.Sprintf("    Processed tags: %+v", processedTags))
@@ -537,24 +1230,142 @@ func cloneSingleRole(ctx context.Context, sourceClient, destClient *awsclient.Cl
 		if err := destClient.TagRole(ctx, destRole, processedTags); err != nil {
 			log.Warning(fmt.Sprintf("    Failed to copy tags: %v", err))
 		} else {
+			recordMutation(config, log, journal.Entry{
+				Action: journal.ActionTagRole, Role: destRole, TagKeys: awsclient.SortedKeys(processedTags),
+			})
 			log.Debug("    Tags copied successfully")
 		}
 	}
 
+	if synced != nil {
+		log.Debug("  Removing what the source role does not have...")
+		pruneSync(ctx, destClient, plan, synced, wantedManaged, wantedInline, log)
+	}
+
+	return nil
+}
+
+// createDestRole creates the destination role with the transformed trust policy
+func createDestRole(ctx context.Context, destClient *awsclient.Client, roleInfo *awsclient.RoleInfo,
+	plan *RolePlan, config *CloneConfig, log *logger.Logger) error {
+
+	log.Debug("  Creating new role...")
+	processedTrustPolicy := plan.TrustPolicy
+
+	// Debug: Show the processed trust policy if verbose
+	if config.Verbose {
+		log.Debug(fmt.Sprintf("  Original trust policy: %s", roleInfo.TrustPolicy))
+		log.Debug(fmt.Sprintf("  Processed trust policy: %s", processedTrustPolicy))
+	}
+
+	if err := destClient.CreateRole(ctx, plan.DestRole, processedTrustPolicy, plan.Description); err != nil {
+		// Enhanced error message with policy content
+		if config.Verbose {
+			log.Error(fmt.Sprintf("  Failed trust policy content: %s", processedTrustPolicy))
+		}
+		return fmt.Errorf("failed to create role: %v", err)
+	}
+	recordMutation(config, log, journal.Entry{
+		Action: journal.ActionCreateRole, Role: plan.DestRole, Hash: documentHash(processedTrustPolicy),
+	})
+
+	log.Debug("  Role created successfully")
 	return nil
 }
 
+// transformTrustPolicy rewrites IRSA and GitHub Actions trust, applies pattern
+// replacement and points provider ARNs at the destination. Warnings describe
+// conditions that deserve a closer look before cloning.
+func transformTrustPolicy(trustPolicy string, config *CloneConfig) (string, []string, error) {
+	source, _, err := awsclient.RewriteIRSATrust(trustPolicy, config.IRSA)
+	if err != nil {
+		return "", nil, err
+	}
+
+	processed := awsclient.ReplacePatternInJSON(source, config.SourcePattern, config.DestPattern)
+
+	processed, warnings, err := awsclient.RewriteGitHubTrust(source, processed, config.GitHub)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return awsclient.RewriteProviderArns(processed, config.SourceAccount, config.DestAccount), warnings, nil
+}
+
+// mapProviderArn returns the ARN that the trust policy of role names in place of
+// a source provider, applying the transformations of transformTrustPolicy and the
+// role's mapping row in the same order, and whether it was remapped to a
+// different EKS cluster
+func mapProviderArn(arn, role string, config *CloneConfig) (string, bool) {
+	destArn := arn
+	clusterMapped := false
+
+	if account, providerType, name, ok := awsclient.ParseProviderArn(arn); ok && awsclient.IsEKSProvider(name) {
+		if dest, mapped := config.IRSA.MapProvider(name); mapped && dest != name {
+			destArn = fmt.Sprintf("arn:aws:iam::%s:%s/%s", account, providerType, dest)
+			clusterMapped = true
+		}
+	}
+
+	destArn = awsclient.ReplacePatternInJSON(destArn, config.SourcePattern, config.DestPattern)
+	destArn = awsclient.RewriteProviderArns(destArn, config.SourceAccount, config.DestAccount)
+	return config.Mapping.Row(role).Replace(destArn), clusterMapped
+}
+
 func init() {
 	rootCmd.AddCommand(cloneCmd)
 
 	// Command-specific flags
 	cloneCmd.Flags().StringP("source-profile", "s", "", "Source AWS profile")
 	cloneCmd.Flags().StringP("dest-profile", "d", "", "Destination AWS profile")
-	cloneCmd.Flags().String("source-pattern", "", "Source environment pattern (e.g., 'dev_')")
-	cloneCmd.Flags().String("dest-pattern", "", "Destination environment pattern (e.g., 'prod_')")
 	cloneCmd.Flags().String("log-file", "", "Log file path (default: auto-generated)")
+	cloneCmd.Flags().String("journal", "", "Journal file recording every change, for undo (default: iam-clone-<run-id>.journal.jsonl)")
+	cloneCmd.Flags().Bool("create-providers", false, "Create OIDC/SAML providers referenced by trust policies if missing in destination")
+	cloneCmd.Flags().String("block-severity", "", "Abort when a privilege-escalation finding is at or above this severity (low, medium, high, critical)")
+	addCloneOptionFlags(cloneCmd)
+	addSelectorFlags(cloneCmd)
+	cloneCmd.Flags().String("on-collision", awsclient.CollisionFail, "What to do when a destination name is taken (fail, skip, suffix, sync)")
+	cloneCmd.Flags().Int("skip-unused-days", 0, "Leave out roles not used in this many days (0 keeps all)")
+	cloneCmd.Flags().String("dependencies", DependenciesAsk, "Roles passed or assumed by the selected roles: ask, include or ignore")
+	cloneCmd.Flags().Bool("no-tui", false, "Use the numbered selection prompt instead of the full-screen role picker")
+	cloneCmd.Flags().Bool("strict-leakage", false, "Fail roles whose transformed names, documents or tags still reference the source environment")
 
 	// Global flags
 	cloneCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
 	cloneCmd.Flags().Bool("dry-run", false, "Show what would be done without actually doing it")
 }
+
+// runStartTime recovers the start of a run from its ID
+func runStartTime(runID string) (time.Time, bool) {
+	if len(runID) < len("20060102-150405") {
+		return time.Time{}, false
+	}
+	startedAt, err := time.ParseInLocation("20060102-150405", runID[:len("20060102-150405")], time.Local)
+	return startedAt, err == nil
+}
+
+// newRunID identifies a clone run, e.g. "20250101-120000-9f86d081884c7d65".
+// The random suffix keeps runs started in the same second apart.
+func newRunID(startedAt time.Time) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %v", err)
+	}
+	return fmt.Sprintf("%s-%x", startedAt.Format("20060102-150405"), suffix), nil
+}
+
+// recordMutation appends a destination change to the run journal
+func recordMutation(config *CloneConfig, log *logger.Logger, entry journal.Entry) {
+	if err := config.Journal.Record(entry); err != nil {
+		log.Warning(fmt.Sprintf("    Failed to record %s in journal: %v", entry.Action, err))
+	}
+}
+
+// Helper function to hash a policy document for the journal
+func documentHash(document string) string {
+	hash, err := awsclient.PolicyHash(document)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
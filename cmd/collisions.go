@@ -0,0 +1,197 @@
+// cmd/collisions.go - Destination name collisions and in-place sync of existing roles
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/journal"
+	"iam-role-cloner/internal/logger"
+)
+
+// resolveCollisions finds destination names taken inside the batch or in the
+// destination account and applies the --on-collision strategy
+func resolveCollisions(ctx context.Context, config *CloneConfig, log *logger.Logger) error {
+	destClient, err := awsclient.NewClient(config.DestProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create destination client: %v", err)
+	}
+
+	existing, err := destClient.ListRoles(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list destination roles: %v", err)
+	}
+
+	destNames := make(map[string]string, len(config.Roles))
+	for _, role := range config.Roles {
+		destNames[role] = destRoleName(config, role)
+	}
+
+	collisions := awsclient.FindNameCollisions(config.Roles, destNames, existing)
+	if len(collisions) == 0 {
+		log.Success("No destination name collisions")
+		return nil
+	}
+
+	log.Warning(fmt.Sprintf("%d destination name collision(s) (strategy: %s):", len(collisions), config.OnCollision))
+	for _, collision := range collisions {
+		log.Warning(fmt.Sprintf("  %s", collision))
+	}
+
+	switch config.OnCollision {
+	case awsclient.CollisionSkip:
+		skipped := make(map[string]bool)
+		for _, collision := range collisions {
+			skipped[collision.SourceRole] = true
+		}
+		var roles []string
+		for _, role := range config.Roles {
+			if !skipped[role] {
+				roles = append(roles, role)
+			}
+		}
+		config.Roles = roles
+		log.Info(fmt.Sprintf("Skipping %d role(s), %d left to clone", len(skipped), len(config.Roles)))
+
+	case awsclient.CollisionSuffix:
+		taken := make(map[string]bool)
+		for _, name := range existing {
+			taken[strings.ToLower(name)] = true
+		}
+		for _, name := range destNames {
+			taken[strings.ToLower(name)] = true
+		}
+		if config.DestNames == nil {
+			config.DestNames = make(map[string]string)
+		}
+		for _, collision := range collisions {
+			renamed := awsclient.SuffixRoleName(collision.DestRole, taken, config.Limits.RoleNameLength)
+			taken[strings.ToLower(renamed)] = true
+			config.DestNames[collision.SourceRole] = renamed
+			log.Info(fmt.Sprintf("  %s → %s", collision.SourceRole, renamed))
+		}
+
+	case awsclient.CollisionSync:
+		config.SyncTargets = make(map[string]*awsclient.RoleInfo)
+		var unsyncable []string
+		for _, collision := range collisions {
+			if collision.Kind == awsclient.CollisionBatch {
+				unsyncable = append(unsyncable, collision.SourceRole)
+				continue
+			}
+			current, err := destClient.GetRoleInfo(ctx, collision.DestRole)
+			if err != nil {
+				return fmt.Errorf("failed to read existing role %s: %v", collision.DestRole, err)
+			}
+			config.SyncTargets[collision.SourceRole] = current
+		}
+		if len(unsyncable) > 0 {
+			return fmt.Errorf("cannot sync several source roles into one destination role: %s",
+				strings.Join(unsyncable, ", "))
+		}
+		log.Info(fmt.Sprintf("%d existing role(s) will be updated in place", len(config.SyncTargets)))
+
+	default:
+		return fmt.Errorf("%d destination name collision(s); use --on-collision skip, suffix or sync", len(collisions))
+	}
+
+	return nil
+}
+
+// describeSync lists what syncing an existing role to its plan would change
+func describeSync(plan *RolePlan, current *awsclient.RoleInfo, config *CloneConfig) ([]string, error) {
+	expected := &awsclient.RoleInfo{
+		RoleName:        plan.DestRole,
+		TrustPolicy:     plan.TrustPolicy,
+		ManagedPolicies: expectedManagedPolicies(plan, current, config),
+		InlinePolicies:  plan.InlinePolicies,
+		Tags:            plan.Tags,
+	}
+
+	changes, err := awsclient.DiffRoles(expected, current, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	if plan.Description != current.Description {
+		lines = append(lines, fmt.Sprintf("description: %q → %q", current.Description, plan.Description))
+	}
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	return lines, nil
+}
+
+// prepareSync updates the trust policy and description of an existing role and
+// marks it as synced in the journal, so undo leaves it alone
+func prepareSync(ctx context.Context, destClient *awsclient.Client, plan *RolePlan,
+	config *CloneConfig, log *logger.Logger) (*awsclient.RoleInfo, error) {
+
+	current, err := destClient.GetRoleInfo(ctx, plan.DestRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing role: %v", err)
+	}
+
+	recordMutation(config, log, journal.Entry{Action: journal.ActionSyncRole, Role: plan.DestRole})
+
+	if documentHash(current.TrustPolicy) != documentHash(plan.TrustPolicy) {
+		if err := destClient.UpdateTrustPolicy(ctx, plan.DestRole, plan.TrustPolicy); err != nil {
+			return nil, err
+		}
+		log.Debug("  Updated trust policy")
+	}
+
+	if current.Description != plan.Description {
+		if err := destClient.UpdateRoleDescription(ctx, plan.DestRole, plan.Description); err != nil {
+			return nil, err
+		}
+		log.Debug("  Updated description")
+	}
+
+	return current, nil
+}
+
+// pruneSync removes the managed policies, inline policies and tags of a synced
+// role that the plan does not have
+func pruneSync(ctx context.Context, destClient *awsclient.Client, plan *RolePlan, current *awsclient.RoleInfo,
+	wantedManaged, wantedInline map[string]bool, log *logger.Logger) {
+
+	for _, policyArn := range current.ManagedPolicies {
+		if wantedManaged[policyArn] {
+			continue
+		}
+		if err := destClient.DetachManagedPolicy(ctx, plan.DestRole, policyArn); err != nil {
+			log.Warning(fmt.Sprintf("    %v", err))
+		} else {
+			log.Debug(fmt.Sprintf("    Detached: %s", policyArn))
+		}
+	}
+
+	for policyName := range current.InlinePolicies {
+		if wantedInline[policyName] {
+			continue
+		}
+		if err := destClient.DeleteInlinePolicy(ctx, plan.DestRole, policyName); err != nil {
+			log.Warning(fmt.Sprintf("    %v", err))
+		} else {
+			log.Debug(fmt.Sprintf("    Deleted inline policy: %s", policyName))
+		}
+	}
+
+	var staleTags []string
+	for key := range current.Tags {
+		if _, ok := plan.Tags[key]; !ok {
+			staleTags = append(staleTags, key)
+		}
+	}
+	sort.Strings(staleTags)
+	if err := destClient.UntagRole(ctx, plan.DestRole, staleTags); err != nil {
+		log.Warning(fmt.Sprintf("    %v", err))
+	} else if len(staleTags) > 0 {
+		log.Debug(fmt.Sprintf("    Removed tags: %s", strings.Join(staleTags, ", ")))
+	}
+}
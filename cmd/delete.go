@@ -0,0 +1,311 @@
+// cmd/delete.go - Delete roles created by the tool
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// DeleteConfig holds the options of the delete command
+type DeleteConfig struct {
+	Profile          string
+	Roles            []string
+	Pattern          string
+	RunID            string
+	Selector         *awsclient.RoleSelector
+	Protected        []string
+	ConfirmThreshold int
+	DeleteProfiles   bool
+	Yes              bool
+	DryRun           bool
+	Verbose          bool
+	LogFile          string
+}
+
+// deleteCmd removes roles together with their attachments
+var deleteCmd = &cobra.Command{
+	Use:     "delete [role...]",
+	Aliases: []string{"prune"},
+	Short:   "Delete IAM roles, e.g. a bad clone batch",
+	Long: `Delete destination roles selected by name, prefix, clone run ID or role
+selector. A selector given with other criteria narrows them; on its own it
+selects from all roles.
+
+For every role the command shows a plan, then removes it from its instance
+profiles, detaches managed policies, deletes inline policies and finally deletes
+the role. Roles matching a protected pattern are never touched; service-linked,
+SSO and OrganizationAccountAccessRole roles are always protected.
+
+Deleting more than --confirm-threshold roles requires typing the confirmation.
+
+Examples:
+  iam-role-cloner delete -p prod prod_app_role prod_worker_role
+  iam-role-cloner delete -p prod --pattern "prod_tmp_" --dry-run
+  iam-role-cloner delete -p prod --run-id 20250101-120000-9f86d081884c7d65
+  iam-role-cloner delete -p prod --select 'prod_* and lastused>180d' --dry-run
+  iam-role-cloner prune -p prod --pattern "prod_" --protect "prod_admin*"`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, _ := cmd.Flags().GetString("profile")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		runID, _ := cmd.Flags().GetString("run-id")
+		protected, _ := cmd.Flags().GetStringSlice("protect")
+		confirmThreshold, _ := cmd.Flags().GetInt("confirm-threshold")
+		deleteProfiles, _ := cmd.Flags().GetBool("delete-instance-profiles")
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		logFile, _ := cmd.Flags().GetString("log-file")
+
+		selector, err := buildRoleSelector(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) == 0 && pattern == "" && runID == "" && selector.IsEmpty() {
+			fmt.Println("❌ Error: select roles by name, --pattern, --run-id or --select/--include/--exclude")
+			os.Exit(1)
+		}
+
+		if logFile == "" {
+			logFile = fmt.Sprintf("iam-delete-%s.log", time.Now().Format("20060102-150405"))
+		}
+
+		config := &DeleteConfig{
+			Profile:          profile,
+			Roles:            args,
+			Pattern:          pattern,
+			RunID:            runID,
+			Selector:         selector,
+			Protected:        append(append([]string{}, awsclient.DefaultProtectedRoles...), protected...),
+			ConfirmThreshold: confirmThreshold,
+			DeleteProfiles:   deleteProfiles,
+			Yes:              yes,
+			DryRun:           dryRun,
+			Verbose:          verbose,
+			LogFile:          logFile,
+		}
+
+		if err := runDelete(config); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runDelete(config *DeleteConfig) error {
+	log, err := logger.New(config.Verbose, config.LogFile)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+
+	log.Header(fmt.Sprintf("🗑️  Delete Roles in Profile: %s", config.Profile))
+
+	client, err := awsclient.NewClient(config.Profile)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	ctx := context.Background()
+	identity, err := client.ValidateCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials: %v", err)
+	}
+	log.Success(fmt.Sprintf("Connected to AWS Account: %s", *identity.Account))
+
+	roles, err := selectRolesForDeletion(ctx, client, config, log)
+	if err != nil {
+		return err
+	}
+
+	var plans []*awsclient.RoleDeletionPlan
+	for _, role := range roles {
+		if awsclient.IsProtectedRole(role, config.Protected) {
+			log.Warning(fmt.Sprintf("Skipping protected role: %s", role))
+			continue
+		}
+
+		plan, err := client.PlanRoleDeletion(ctx, role)
+		if err != nil {
+			log.Error(fmt.Sprintf("Cannot plan deletion of %s: %v", role, err))
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	if len(plans) == 0 {
+		log.Info("No roles to delete")
+		return nil
+	}
+
+	showDeletionPlan(plans, config, log)
+
+	if config.DryRun {
+		log.Info("This was a dry run. Use without --dry-run to delete these roles.")
+		return nil
+	}
+
+	if !confirmDeletion(len(plans), config, bufio.NewReader(os.Stdin)) {
+		log.Info("Deletion cancelled")
+		return nil
+	}
+
+	log.Separator()
+	deleted := 0
+	for i, plan := range plans {
+		log.Progress(i+1, len(plans), fmt.Sprintf("Deleting: %s", plan.RoleName))
+		if err := client.DeleteRole(ctx, plan, config.DeleteProfiles); err != nil {
+			log.Error(fmt.Sprintf("Failed to delete %s: %v", plan.RoleName, err))
+			continue
+		}
+		deleted++
+		log.Success(fmt.Sprintf("Deleted: %s", plan.RoleName))
+	}
+
+	log.Separator()
+	if deleted < len(plans) {
+		return fmt.Errorf("deleted %d of %d roles, see %s", deleted, len(plans), config.LogFile)
+	}
+	log.Success(fmt.Sprintf("Deleted %d roles", deleted))
+
+	return nil
+}
+
+// selectRolesForDeletion resolves names, prefix, run ID and selector to a sorted role list
+func selectRolesForDeletion(ctx context.Context, client *awsclient.Client, config *DeleteConfig, log *logger.Logger) ([]string, error) {
+	selected := make(map[string]bool)
+
+	for _, role := range config.Roles {
+		if !client.RoleExists(ctx, role) {
+			log.Warning(fmt.Sprintf("Role not found: %s", role))
+			continue
+		}
+		selected[role] = true
+	}
+
+	if config.Pattern != "" || config.RunID != "" {
+		roles, err := client.ListRoles(ctx, config.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, role := range roles {
+			if config.RunID == "" {
+				selected[role] = true
+				continue
+			}
+
+			tags, err := client.GetRoleTags(ctx, role)
+			if err != nil {
+				log.Debug(fmt.Sprintf("Skipping %s: %v", role, err))
+				continue
+			}
+			if provenance, ok := awsclient.ParseProvenance(tags); ok && provenance.RunID == config.RunID {
+				selected[role] = true
+			}
+		}
+	}
+
+	// The selector narrows the other criteria, or selects from all roles on its own
+	if !config.Selector.IsEmpty() {
+		summaries, err := client.ListRoleSummaries(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		selectorOnly := len(config.Roles) == 0 && config.Pattern == "" && config.RunID == ""
+		var candidates []*awsclient.RoleSummary
+		for _, role := range summaries {
+			if selectorOnly || selected[role.Name] {
+				candidates = append(candidates, role)
+			}
+		}
+
+		selected = make(map[string]bool)
+		for _, role := range filterRoleSummaries(ctx, client, candidates, config.Selector, log) {
+			selected[role.Name] = true
+		}
+	}
+
+	roles := make([]string, 0, len(selected))
+	for role := range selected {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	return roles, nil
+}
+
+func showDeletionPlan(plans []*awsclient.RoleDeletionPlan, config *DeleteConfig, log *logger.Logger) {
+	log.Separator()
+	log.Info(fmt.Sprintf("Deletion plan (%d roles):", len(plans)))
+
+	for i, plan := range plans {
+		fmt.Printf("  %d. %s\n", i+1, plan.RoleName)
+		for _, profile := range plan.InstanceProfiles {
+			action := "remove from"
+			if config.DeleteProfiles {
+				action = "remove from and delete"
+			}
+			fmt.Printf("       - %s instance profile %s\n", action, profile)
+		}
+		for _, policyArn := range plan.ManagedPolicies {
+			fmt.Printf("       - detach %s\n", policyArn)
+		}
+		for _, policyName := range plan.InlinePolicies {
+			fmt.Printf("       - delete inline policy %s\n", policyName)
+		}
+		fmt.Println("       - delete role")
+	}
+}
+
+// confirmDeletion asks for y/n, or for a typed phrase above the threshold
+func confirmDeletion(count int, config *DeleteConfig, reader *bufio.Reader) bool {
+	if count > config.ConfirmThreshold {
+		phrase := fmt.Sprintf("delete %d roles", count)
+		fmt.Printf("\nType '%s' to confirm: ", phrase)
+		answer, _ := reader.ReadString('\n')
+		return strings.TrimSpace(answer) == phrase
+	}
+
+	if config.Yes {
+		return true
+	}
+
+	fmt.Printf("\nDelete %d role(s)? (y/n): ", count)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	// Required flags
+	deleteCmd.Flags().StringP("profile", "p", "", "AWS profile to delete roles from (required)")
+	deleteCmd.MarkFlagRequired("profile")
+
+	// Optional flags
+	deleteCmd.Flags().String("pattern", "", "Delete roles starting with this prefix")
+	deleteCmd.Flags().String("run-id", "", "Delete roles tagged with this clone run ID (see clone --provenance)")
+	deleteCmd.Flags().StringSlice("protect", nil, "Role name patterns that must never be deleted (e.g., 'prod_admin*')")
+	deleteCmd.Flags().Int("confirm-threshold", 5, "Require typed confirmation when deleting more roles than this")
+	deleteCmd.Flags().Bool("delete-instance-profiles", false, "Also delete the instance profiles the roles are removed from")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Skip the y/n prompt (typed confirmation is still required above the threshold)")
+	deleteCmd.Flags().Bool("dry-run", false, "Show the deletion plan without deleting anything")
+	deleteCmd.Flags().String("log-file", "", "Log file path (default: auto-generated)")
+	deleteCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	addSelectorFlags(deleteCmd)
+}
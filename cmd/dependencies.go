@@ -0,0 +1,269 @@
+// cmd/dependencies.go - Roles that cloned roles pass, assume or trust
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// How roles referenced by the batch are handled
+const (
+	DependenciesAsk     = "ask"
+	DependenciesInclude = "include"
+	DependenciesIgnore  = "ignore"
+)
+
+// resolveDependencies finds the roles that the batch may pass or assume, adds the
+// ones outside the batch according to --dependencies, and puts the batch in
+// dependency order. Roles named as principals in the transformed trust policies
+// of the batch are created first, since IAM rejects a trust policy naming a role
+// that does not exist.
+func resolveDependencies(ctx context.Context, sourceClient *awsclient.Client, config *CloneConfig,
+	log *logger.Logger, reader *bufio.Reader) error {
+
+	knownRoles, err := sourceClient.ListRoles(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list source roles: %v", err)
+	}
+	exists := make(map[string]bool, len(knownRoles))
+	for _, role := range knownRoles {
+		exists[role] = true
+	}
+
+	if config.ManagedPolicyDocs == nil {
+		config.ManagedPolicyDocs = make(map[string]string)
+	}
+
+	inBatch := make(map[string]bool, len(config.Roles))
+	for _, role := range config.Roles {
+		inBatch[role] = true
+	}
+
+	// Follow references breadth-first so roles needed by referenced roles are found too
+	var references []awsclient.RoleReference
+	var extra []string
+	var missing []string
+	scanned := make(map[string]bool)
+	queue := append([]string(nil), config.Roles...)
+
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+		if scanned[role] {
+			continue
+		}
+		scanned[role] = true
+
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			roleInfo, err = sourceClient.GetRoleInfo(ctx, role)
+			if err != nil {
+				log.Warning(fmt.Sprintf("Could not fetch %s for dependency analysis: %v", role, err))
+				continue
+			}
+			config.RoleInfos[role] = roleInfo
+		}
+
+		documents := rolePolicyDocuments(ctx, sourceClient, config.ManagedPolicyDocs, roleInfo, log)
+		found, err := awsclient.FindRoleReferences(role, documents,
+			config.SourceAccount, knownRoles)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not analyze dependencies of %s: %v", role, err))
+			continue
+		}
+
+		for _, ref := range found {
+			if !exists[ref.ToRole] {
+				if !awsclient.Contains(missing, ref.ToRole) {
+					missing = append(missing, ref.ToRole)
+				}
+				continue
+			}
+			references = append(references, ref)
+
+			// A pattern like dev_* orders the batch but does not pull in every matching role
+			if !inBatch[ref.ToRole] && !scanned[ref.ToRole] && !ref.Wildcard {
+				queue = append(queue, ref.ToRole)
+				if !awsclient.Contains(extra, ref.ToRole) {
+					extra = append(extra, ref.ToRole)
+				}
+			}
+		}
+	}
+
+	sort.Strings(missing)
+	for _, role := range missing {
+		log.Warning(fmt.Sprintf("Policies reference role %s, which does not exist in the source account", role))
+	}
+
+	if len(extra) > 0 && includeDependencies(extra, references, config, log, reader) {
+		config.Roles = append(config.Roles, extra...)
+		log.Info(fmt.Sprintf("Added %d referenced role(s) to the batch", len(extra)))
+	} else if len(extra) > 0 {
+		log.Warning(fmt.Sprintf("Not cloning %d referenced role(s); the cloned policies will point at roles that may not exist: %s",
+			len(extra), strings.Join(extra, ", ")))
+	}
+
+	references = append(references, trustDependencies(knownRoles, config, log)...)
+	if len(references) == 0 {
+		log.Success("No role dependencies found")
+		return nil
+	}
+
+	ordered, cycles := awsclient.DependencyOrder(config.Roles, awsclient.OrderingReferences(references))
+	config.Roles = ordered
+	for _, cycle := range cycles {
+		log.Warning(fmt.Sprintf("Dependency cycle, cloned in batch order: %s", strings.Join(cycle, " ↔ ")))
+	}
+
+	config.Dependencies = references
+	log.Success(fmt.Sprintf("Found %d role dependency reference(s); batch is in dependency order", len(references)))
+
+	return nil
+}
+
+// trustDependencies returns a reference from each batch role to the batch roles
+// whose destination names its transformed trust policy has as principals. Clones
+// between accounts keep the source account in trust principals, so these only
+// occur when environments share an account.
+func trustDependencies(knownRoles []string, config *CloneConfig, log *logger.Logger) []awsclient.RoleReference {
+	// Trust policies name destination roles; find the source role cloned to each name
+	sourceOf := make(map[string]string, len(knownRoles))
+	for _, role := range knownRoles {
+		destRole := destRoleName(config, role)
+		if destRole == role && config.SourceAccount == config.DestAccount {
+			// The role itself already exists under that name
+			continue
+		}
+		sourceOf[destRole] = role
+	}
+
+	var references []awsclient.RoleReference
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			continue
+		}
+
+		trustPolicy, _, err := transformTrustPolicy(roleInfo.TrustPolicy, config)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not analyze the trust policy of %s: %v", role, err))
+			continue
+		}
+		trustPolicy = config.Mapping.Row(role).Replace(trustPolicy)
+
+		found, err := awsclient.FindTrustDependencies(role, trustPolicy, config.DestAccount)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not analyze the trust policy of %s: %v", role, err))
+			continue
+		}
+
+		for _, ref := range found {
+			// Names no source role is cloned to must already exist in the destination
+			source, ok := sourceOf[ref.ToRole]
+			if !ok || source == role {
+				continue
+			}
+			ref.ToRole = source
+			references = append(references, ref)
+		}
+	}
+
+	return references
+}
+
+// Helper function to decide whether roles referenced from the batch are cloned too
+func includeDependencies(extra []string, references []awsclient.RoleReference, config *CloneConfig,
+	log *logger.Logger, reader *bufio.Reader) bool {
+
+	switch config.DependencyMode {
+	case DependenciesInclude:
+		return true
+	case DependenciesIgnore:
+		return false
+	}
+
+	log.Warning(fmt.Sprintf("%d role(s) outside the batch are passed or assumed by the batch:", len(extra)))
+	for _, role := range extra {
+		var users []string
+		for _, ref := range references {
+			if ref.ToRole == role {
+				users = append(users, fmt.Sprintf("%s (%s)", ref.FromRole, ref.Action))
+			}
+		}
+		fmt.Printf("  • %s ← %s\n", role, strings.Join(awsclient.Dedupe(users), ", "))
+	}
+
+	fmt.Print("Include them in the batch? (y/n): ")
+	confirm, _ := reader.ReadString('\n')
+	confirm = strings.ToLower(strings.TrimSpace(confirm))
+
+	return confirm == "y" || confirm == "yes"
+}
+
+// rolePolicyDocuments returns the inline and managed policy documents of a role,
+// keyed by inline policy name or managed policy ARN. Managed documents are kept in
+// cache; policies that cannot be read are skipped with a warning.
+func rolePolicyDocuments(ctx context.Context, client *awsclient.Client, cache map[string]string,
+	roleInfo *awsclient.RoleInfo, log *logger.Logger) map[string]string {
+
+	documents := make(map[string]string)
+	for name, document := range roleInfo.InlinePolicies {
+		documents[name] = document
+	}
+
+	for _, policyArn := range roleInfo.ManagedPolicies {
+		document, cached := cache[policyArn]
+		if !cached {
+			var err error
+			document, err = client.GetManagedPolicyDocument(ctx, policyArn)
+			if err != nil {
+				log.Warning(fmt.Sprintf("Could not fetch %s for analysis: %v", policyArn, err))
+				continue
+			}
+			cache[policyArn] = document
+		}
+		documents[policyArn] = document
+	}
+
+	return documents
+}
+
+// roleDependencies returns the references from a role to other roles in the batch
+func roleDependencies(config *CloneConfig, role string) []awsclient.RoleReference {
+	var deps []awsclient.RoleReference
+	for _, ref := range config.Dependencies {
+		if ref.FromRole == role && awsclient.Contains(config.Roles, ref.ToRole) {
+			deps = append(deps, ref)
+		}
+	}
+	return deps
+}
+
+// showDependencyGraph prints each batch role with the roles it needs, in clone order
+func showDependencyGraph(config *CloneConfig) {
+	fmt.Println("\nDependency graph (clone order):")
+
+	for i, role := range config.Roles {
+		fmt.Printf("  %d. %s → %s\n", i+1, role, destRoleName(config, role))
+		for _, ref := range roleDependencies(config, role) {
+			if ref.Action == awsclient.DependencyTrust {
+				fmt.Printf("       └─ trusts %s → %s\n", ref.ToRole, destRoleName(config, ref.ToRole))
+				continue
+			}
+			fmt.Printf("       └─ %s %s → %s (%s)\n", ref.Action, ref.ToRole, destRoleName(config, ref.ToRole), ref.Policy)
+
+			// Policies are rewritten by pattern, so a differently named dependency is not followed
+			rewritten := awsclient.GenerateNewRoleName(ref.ToRole, config.SourcePattern, config.DestPattern)
+			if rewritten != destRoleName(config, ref.ToRole) {
+				fmt.Printf("          ⚠️  the cloned policy will reference %s, not %s\n", rewritten, destRoleName(config, ref.ToRole))
+			}
+		}
+	}
+}
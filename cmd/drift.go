@@ -0,0 +1,450 @@
+// cmd/drift.go - Detect drift between source roles and their clones
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/journal"
+	"iam-role-cloner/internal/logger"
+)
+
+// Drift states of a source/destination pair
+const (
+	DriftInSync        = "in-sync"
+	DriftDrifted       = "drifted"
+	DriftSourceMissing = "source-missing"
+	DriftError         = "error"
+)
+
+// Ways a destination role is paired with its source
+const (
+	PairedByProvenance = "provenance"
+	PairedByMapping    = "mapping"
+	PairedByPattern    = "pattern"
+)
+
+// RoleDrift is the drift report for one cloned role
+type RoleDrift struct {
+	SourceRole string `json:"source_role"`
+	DestRole   string `json:"dest_role"`
+	PairedBy   string `json:"paired_by"`
+	// RunID is set when the options of the run that cloned the role were used
+	RunID   string                  `json:"run_id,omitempty"`
+	Status  string                  `json:"status"`
+	Changes []awsclient.DriftChange `json:"changes,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// rolePair is a destination role and the source it should mirror
+type rolePair struct {
+	SourceRole string
+	DestRole   string
+	PairedBy   string
+	Provenance *awsclient.Provenance
+}
+
+// driftCmd compares cloned roles with the expected transformation of their source
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Detect drift between source roles and their clones",
+	Long: `Pair each destination role with its source, recompute the expected cloned
+state from the current source role, and report what differs:
+added, removed and changed trust statements, managed policies, inline policy
+statements and tags.
+
+Roles are paired by provenance tags (written by 'clone --provenance') and, when
+--mapping or --source-pattern and --dest-pattern are given, by the mapping file
+or pattern replacement of source role names.
+
+A role paired by provenance is checked with the options of the run that cloned
+it when that run's journal is in --journal-dir. Every other role is checked with
+the clone option flags given to drift (patterns, IRSA and GitHub maps, policy
+handling, mapping file, tag rules and description templates), so pass the same
+flags as the original clone.
+
+Exits with status 2 when any role has drifted, so it can run on a schedule.
+
+Examples:
+  iam-role-cloner drift --source-profile dev --dest-profile prod
+  iam-role-cloner drift --source-profile dev --dest-profile prod --source-pattern "dev_" --dest-pattern "prod_"
+  iam-role-cloner drift --source-profile dev --dest-profile prod --journal-dir ./journals
+  iam-role-cloner drift --source-profile legacy --dest-profile prod --mapping roles.yaml --share-inline-policies
+  iam-role-cloner drift --source-profile dev --dest-profile prod --output json > drift.json`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceProfile, _ := cmd.Flags().GetString("source-profile")
+		destProfile, _ := cmd.Flags().GetString("dest-profile")
+		journalDir, _ := cmd.Flags().GetString("journal-dir")
+		output, _ := cmd.Flags().GetString("output")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if output != "table" && output != "json" {
+			fmt.Printf("❌ Error: unsupported output format '%s' (use 'table' or 'json')\n", output)
+			os.Exit(1)
+		}
+
+		opts, err := readCloneOptions(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		config, err := newCloneConfig(opts, time.Now())
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		config.SourceProfile = sourceProfile
+		config.DestProfile = destProfile
+		config.Verbose = verbose
+
+		reports, err := runDrift(config, journalDir, output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, report := range reports {
+			if report.Status != DriftInSync {
+				os.Exit(2)
+			}
+		}
+	},
+}
+
+func runDrift(config *CloneConfig, journalDir, output string) ([]RoleDrift, error) {
+	table := output == "table"
+
+	log, err := logger.New(config.Verbose, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+	if !table {
+		log.SetOutput(os.Stderr)
+	}
+
+	if table {
+		log.Header(fmt.Sprintf("🔀 Drift: %s → %s", config.SourceProfile, config.DestProfile))
+	}
+
+	sourceClient, err := awsclient.NewClient(config.SourceProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source client: %v", err)
+	}
+	destClient, err := awsclient.NewClient(config.DestProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create destination client: %v", err)
+	}
+
+	ctx := context.Background()
+	sourceIdentity, err := sourceClient.ValidateCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate source credentials: %v", err)
+	}
+	destIdentity, err := destClient.ValidateCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate destination credentials: %v", err)
+	}
+	config.SourceAccount = *sourceIdentity.Account
+	config.DestAccount = *destIdentity.Account
+
+	runs := loadRunConfigs(journalDir, config, log)
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s.Suffix = " Pairing and comparing roles..."
+	if table {
+		s.Start()
+	}
+	pairs, err := pairClonedRoles(ctx, sourceClient, destClient, config, log)
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+
+	reports := make([]RoleDrift, 0, len(pairs))
+	for _, pair := range pairs {
+		reports = append(reports, checkDrift(ctx, sourceClient, destClient, pair, config, runs))
+	}
+	s.Stop()
+
+	if !table {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return reports, encoder.Encode(reports)
+	}
+
+	log.Separator()
+	if len(reports) == 0 {
+		log.Info("No cloned roles found (use --provenance when cloning, or pass --source-pattern and --dest-pattern or --mapping)")
+		return reports, nil
+	}
+
+	drifted := 0
+	for _, report := range reports {
+		switch report.Status {
+		case DriftInSync:
+			log.Success(fmt.Sprintf("%s → %s: in sync", report.SourceRole, report.DestRole))
+		case DriftSourceMissing:
+			drifted++
+			log.Warning(fmt.Sprintf("%s → %s: source role no longer exists", report.SourceRole, report.DestRole))
+		case DriftError:
+			drifted++
+			log.Error(fmt.Sprintf("%s → %s: %s", report.SourceRole, report.DestRole, report.Error))
+		default:
+			drifted++
+			log.Warning(fmt.Sprintf("%s → %s: %d difference(s)", report.SourceRole, report.DestRole, len(report.Changes)))
+			for _, change := range report.Changes {
+				fmt.Printf("    %s\n", change)
+			}
+		}
+	}
+
+	log.Separator()
+	if drifted > 0 {
+		log.Warning(fmt.Sprintf("%d of %d cloned roles have drifted", drifted, len(reports)))
+	} else {
+		log.Success(fmt.Sprintf("All %d cloned roles are in sync", len(reports)))
+	}
+
+	return reports, nil
+}
+
+// pairClonedRoles finds destination roles and their sources, by provenance first
+// and then by the mapping file or pattern replacement
+func pairClonedRoles(ctx context.Context, sourceClient, destClient *awsclient.Client,
+	config *CloneConfig, log *logger.Logger) ([]rolePair, error) {
+
+	destRoles, err := destClient.ListRoles(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	destExists := make(map[string]bool, len(destRoles))
+	paired := make(map[string]rolePair)
+
+	for _, role := range destRoles {
+		destExists[role] = true
+
+		tags, err := destClient.GetRoleTags(ctx, role)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Skipping %s: %v", role, err))
+			continue
+		}
+
+		provenance, ok := awsclient.ParseProvenance(tags)
+		if !ok || provenance.SourceAccount != config.SourceAccount {
+			continue
+		}
+		paired[role] = rolePair{
+			SourceRole: provenance.SourceRoleName(),
+			DestRole:   role,
+			PairedBy:   PairedByProvenance,
+			Provenance: &provenance,
+		}
+	}
+
+	var sourceRoles []string
+	pairedBy := PairedByPattern
+	if config.Mapping != nil {
+		sourceRoles = config.Mapping.Sources()
+		pairedBy = PairedByMapping
+	} else if config.SourcePattern != "" && config.DestPattern != "" {
+		sourceRoles, err = sourceClient.ListRoles(ctx, config.SourcePattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, role := range sourceRoles {
+		destRole := destRoleName(config, role)
+		if _, ok := paired[destRole]; ok || !destExists[destRole] {
+			continue
+		}
+		paired[destRole] = rolePair{SourceRole: role, DestRole: destRole, PairedBy: pairedBy}
+	}
+
+	pairs := make([]rolePair, 0, len(paired))
+	for _, pair := range paired {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].DestRole < pairs[j].DestRole })
+
+	return pairs, nil
+}
+
+// checkDrift recomputes the expected destination state and diffs it with the actual role
+func checkDrift(ctx context.Context, sourceClient, destClient *awsclient.Client, pair rolePair,
+	config *CloneConfig, runs map[string]*CloneConfig) RoleDrift {
+
+	report := RoleDrift{SourceRole: pair.SourceRole, DestRole: pair.DestRole, PairedBy: pair.PairedBy}
+
+	if !sourceClient.RoleExists(ctx, pair.SourceRole) {
+		report.Status = DriftSourceMissing
+		return report
+	}
+
+	sourceInfo, err := sourceClient.GetRoleInfo(ctx, pair.SourceRole)
+	if err != nil {
+		report.Status, report.Error = DriftError, err.Error()
+		return report
+	}
+	destInfo, err := destClient.GetRoleInfo(ctx, pair.DestRole)
+	if err != nil {
+		report.Status, report.Error = DriftError, err.Error()
+		return report
+	}
+
+	// Use the options of the original run when its journal is found, and render
+	// {{.CloneDate}} with the date of that run when it is known
+	roleConfig := *config
+	if pair.Provenance != nil {
+		if runConfig, ok := runs[pair.Provenance.RunID]; ok {
+			roleConfig = *runConfig
+			report.RunID = pair.Provenance.RunID
+		} else if startedAt, ok := runStartTime(pair.Provenance.RunID); ok {
+			roleConfig.StartedAt = startedAt
+		}
+	}
+
+	plan, err := buildRolePlan(sourceInfo, pair.DestRole, &roleConfig)
+	if err != nil {
+		report.Status, report.Error = DriftError, err.Error()
+		return report
+	}
+
+	expected := &awsclient.RoleInfo{
+		RoleName:        plan.DestRole,
+		TrustPolicy:     plan.TrustPolicy,
+		ManagedPolicies: expectedManagedPolicies(plan, destInfo, &roleConfig),
+		InlinePolicies:  plan.InlinePolicies,
+		Tags:            plan.Tags,
+	}
+
+	report.Changes, err = awsclient.DiffRoles(expected, destInfo, awsclient.ProvenanceTagPrefix)
+	if err != nil {
+		report.Status, report.Error = DriftError, err.Error()
+		return report
+	}
+
+	report.Status = DriftInSync
+	if len(report.Changes) > 0 {
+		report.Status = DriftDrifted
+	}
+	return report
+}
+
+// expectedManagedPolicies lists the policies a clone attaches: the planned managed
+// policies, converted oversize inline policies and, with --share-inline-policies,
+// the shared policy that replaces each inline policy. Shared policies come from the
+// plan when the batch was planned, as in clone; otherwise each inline policy is
+// matched to the shared policy attached to the destination role, as in drift.
+func expectedManagedPolicies(plan *RolePlan, destInfo *awsclient.RoleInfo, config *CloneConfig) []string {
+	policies := append([]string{}, plan.ManagedPolicies...)
+	for _, converted := range plan.ConvertedPolicies {
+		policies = append(policies, customerPolicyArn(config.DestAccount, converted.Name))
+	}
+	for _, name := range plan.SharedPolicies {
+		policies = append(policies, customerPolicyArn(config.DestAccount, name))
+	}
+
+	if !config.ShareInlinePolicies || config.SharedPolicies != nil {
+		return policies
+	}
+
+	attached := make(map[string]bool, len(destInfo.ManagedPolicies))
+	for _, policyArn := range destInfo.ManagedPolicies {
+		attached[policyArn] = true
+	}
+
+	for _, name := range plan.InlinePolicyNames() {
+		policyArn := customerPolicyArn(config.DestAccount, name)
+
+		// A name taken by another document of the batch got a hash suffix
+		if hash, err := awsclient.PolicyHash(plan.InlinePolicies[name]); err == nil {
+			suffixed := customerPolicyArn(config.DestAccount, fmt.Sprintf("%s-%s", name, hash[:8]))
+			if attached[suffixed] {
+				policyArn = suffixed
+			}
+		}
+
+		policies = append(policies, policyArn)
+		delete(plan.InlinePolicies, name)
+	}
+
+	return policies
+}
+
+// loadRunConfigs rebuilds the clone configuration of every journaled run in dir
+// that targeted the destination account, keyed by run ID
+func loadRunConfigs(dir string, config *CloneConfig, log *logger.Logger) map[string]*CloneConfig {
+	runs := make(map[string]*CloneConfig)
+	if dir == "" {
+		return runs
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	for _, path := range paths {
+		run, _, err := journal.Read(path)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Skipping %s: %v", path, err))
+			continue
+		}
+		if len(run.Options) == 0 || run.Account != config.DestAccount {
+			continue
+		}
+
+		var opts cloneOptions
+		if err := json.Unmarshal(run.Options, &opts); err != nil {
+			log.Warning(fmt.Sprintf("Ignoring options in %s: %v", path, err))
+			continue
+		}
+
+		startedAt, ok := runStartTime(run.RunID)
+		if !ok {
+			startedAt = config.StartedAt
+		}
+		runConfig, err := newCloneConfig(opts, startedAt)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Ignoring options in %s: %v", path, err))
+			continue
+		}
+		runConfig.SourceProfile = config.SourceProfile
+		runConfig.DestProfile = config.DestProfile
+		runConfig.SourceAccount = config.SourceAccount
+		runConfig.DestAccount = config.DestAccount
+		runConfig.Verbose = config.Verbose
+		runConfig.RunID = run.RunID
+
+		runs[run.RunID] = runConfig
+		log.Debug(fmt.Sprintf("Using the options of run %s from %s", run.RunID, path))
+	}
+
+	return runs
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+
+	// Required flags
+	driftCmd.Flags().String("source-profile", "", "Source AWS profile (required)")
+	driftCmd.Flags().String("dest-profile", "", "Destination AWS profile (required)")
+	driftCmd.MarkFlagRequired("source-profile")
+	driftCmd.MarkFlagRequired("dest-profile")
+
+	// Optional flags
+	driftCmd.Flags().String("journal-dir", ".", "Directory with clone journals; roles with provenance are checked with the options of their run")
+	driftCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+	driftCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	addCloneOptionFlags(driftCmd)
+}
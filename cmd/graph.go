@@ -0,0 +1,187 @@
+// cmd/graph.go - Export the trust and permission graph of a profile's roles
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// graphCmd renders who can assume which role as DOT, Mermaid or JSON
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the trust and permission graph of IAM roles",
+	Long: `Build a graph of who can assume which role and print it as Graphviz DOT,
+Mermaid or JSON.
+
+Edges come from:
+  trust     principals allowed by a role's trust policy (accounts, roles, services, providers)
+  assume    sts:AssumeRole permissions on roles of the account
+  passrole  iam:PassRole permissions on roles of the account
+
+Edges that leave the account or trust anyone are drawn in red. The graph is
+written to stdout, or to --file; progress and warnings go to stderr.
+
+Examples:
+  iam-role-cloner graph --profile dev | dot -Tsvg > roles.svg
+  iam-role-cloner graph -p dev --select 'trust:aws' --output mermaid
+  iam-role-cloner graph -p prod --pattern prod_ --trust-only --output json --file graph.json`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, _ := cmd.Flags().GetString("profile")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		output, _ := cmd.Flags().GetString("output")
+		file, _ := cmd.Flags().GetString("file")
+		trustOnly, _ := cmd.Flags().GetBool("trust-only")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if output != "dot" && output != "mermaid" && output != "json" {
+			fmt.Printf("❌ Error: unsupported output format '%s' (use 'dot', 'mermaid' or 'json')\n", output)
+			os.Exit(1)
+		}
+
+		selector, err := buildRoleSelector(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := runGraph(profile, pattern, selector, output, file, trustOnly, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runGraph(profile, pattern string, selector *awsclient.RoleSelector, output, file string, trustOnly, verbose bool) error {
+	log, err := logger.New(verbose, "")
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+	log.SetOutput(os.Stderr)
+
+	client, err := awsclient.NewClient(profile)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	ctx := context.Background()
+	identity, err := client.ValidateCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials: %v", err)
+	}
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
+	s.Suffix = " Building role graph..."
+	s.Start()
+	graph, roleCount, err := buildGraph(ctx, client, *identity.Account, pattern, selector, trustOnly, log)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", file, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch output {
+	case "dot":
+		err = graph.WriteDOT(w)
+	case "mermaid":
+		err = graph.WriteMermaid(w)
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(graph)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %v", err)
+	}
+
+	log.Success(fmt.Sprintf("Graph of %d roles: %d nodes, %d edges (%d cross-account)",
+		roleCount, len(graph.Nodes), len(graph.Edges), graph.CrossAccountEdges()))
+
+	return nil
+}
+
+// buildGraph adds the trust policies and role references of the selected roles
+func buildGraph(ctx context.Context, client *awsclient.Client, accountID, pattern string,
+	selector *awsclient.RoleSelector, trustOnly bool, log *logger.Logger) (*awsclient.Graph, int, error) {
+
+	allRoles, err := client.ListRoleSummaries(ctx, "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if pattern != "" {
+		selector.RequireNameContaining(pattern)
+	}
+	roles := filterRoleSummaries(ctx, client, allRoles, selector, log)
+
+	graph := awsclient.NewGraph(accountID)
+	knownRoles := roleSummaryNames(allRoles)
+	managedDocs := make(map[string]string)
+
+	for _, role := range roles {
+		trust, err := awsclient.ParseTrustPolicy(role.TrustPolicy)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Skipping trust policy of %s: %v", role.Name, err))
+			graph.AddRole(role.Name)
+		} else {
+			graph.AddTrust(role.Name, trust)
+		}
+
+		if trustOnly {
+			continue
+		}
+
+		roleInfo, err := client.GetRoleInfo(ctx, role.Name)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Skipping policies of %s: %v", role.Name, err))
+			continue
+		}
+
+		documents := rolePolicyDocuments(ctx, client, managedDocs, roleInfo, log)
+		references, err := awsclient.FindRoleReferences(role.Name, documents, accountID, knownRoles)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Skipping policies of %s: %v", role.Name, err))
+			continue
+		}
+		graph.AddReferences(references)
+	}
+
+	graph.Sort()
+	return graph, len(roles), nil
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	// Required flags
+	graphCmd.Flags().StringP("profile", "p", "", "AWS profile to use (required)")
+	graphCmd.MarkFlagRequired("profile")
+
+	// Optional flags
+	graphCmd.Flags().String("pattern", "", "Only graph roles whose name contains this pattern (case-insensitive)")
+	graphCmd.Flags().StringP("output", "o", "dot", "Output format: dot, mermaid or json")
+	graphCmd.Flags().String("file", "", "Write the graph to this file instead of stdout")
+	graphCmd.Flags().Bool("trust-only", false, "Only use trust policies; skip the PassRole/AssumeRole scan of permission policies")
+	graphCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	addSelectorFlags(graphCmd)
+}
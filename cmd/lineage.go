@@ -0,0 +1,237 @@
+// cmd/lineage.go - Show which destination roles were cloned from which source
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// Source states reported by the lineage command
+const (
+	SourceUnchanged = "unchanged"
+	SourceChanged   = "changed"
+	SourceMissing   = "missing"
+	SourceUnchecked = "unchecked"
+)
+
+// LineageEntry links a destination role to the source it was cloned from
+type LineageEntry struct {
+	DestRole string `json:"dest_role"`
+	awsclient.Provenance
+	SourceStatus string `json:"source_status"`
+}
+
+// lineageCmd reads provenance tags from cloned roles
+var lineageCmd = &cobra.Command{
+	Use:   "lineage",
+	Short: "Show which roles were cloned from which source",
+	Long: `Read the provenance tags written by 'clone --provenance' and show, for every
+cloned role in a profile, its source role, clone run and tool version.
+
+With --source-profile, each source role is fetched again and compared with the
+content hash recorded at clone time:
+  unchanged  trust policy and permissions match the clone
+  changed    the source role changed since it was cloned
+  missing    the source role no longer exists
+
+Examples:
+  iam-role-cloner lineage --profile prod
+  iam-role-cloner lineage -p prod --source-profile dev
+  iam-role-cloner lineage -p prod -s dev --pattern "prod_" --output json`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, _ := cmd.Flags().GetString("profile")
+		sourceProfile, _ := cmd.Flags().GetString("source-profile")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		output, _ := cmd.Flags().GetString("output")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if output != "table" && output != "json" {
+			fmt.Printf("❌ Error: unsupported output format '%s' (use 'table' or 'json')\n", output)
+			os.Exit(1)
+		}
+
+		if err := runLineage(profile, sourceProfile, pattern, output, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runLineage(profile, sourceProfile, pattern, output string, verbose bool) error {
+	table := output == "table"
+
+	log, err := logger.New(verbose, "")
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+	if !table {
+		log.SetOutput(os.Stderr)
+	}
+
+	if table {
+		log.Header(fmt.Sprintf("🧬 Role Lineage for Profile: %s", profile))
+	}
+
+	client, err := awsclient.NewClient(profile)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.ValidateCredentials(ctx); err != nil {
+		return fmt.Errorf("failed to validate credentials: %v", err)
+	}
+
+	var sourceClient *awsclient.Client
+	sourceAccount := ""
+	if sourceProfile != "" {
+		sourceClient, err = awsclient.NewClient(sourceProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create source client: %v", err)
+		}
+		identity, err := sourceClient.ValidateCredentials(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to validate source credentials: %v", err)
+		}
+		sourceAccount = *identity.Account
+	}
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s.Suffix = " Reading provenance tags..."
+	if table {
+		s.Start()
+	}
+	entries, err := collectLineage(ctx, client, sourceClient, sourceAccount, pattern, log)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if !table {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	log.Separator()
+	if len(entries) == 0 {
+		log.Info("No roles with provenance tags found")
+		return nil
+	}
+
+	printLineage(entries)
+
+	changed := 0
+	for _, entry := range entries {
+		if entry.SourceStatus == SourceChanged || entry.SourceStatus == SourceMissing {
+			changed++
+		}
+	}
+
+	log.Separator()
+	if sourceClient == nil {
+		log.Info(fmt.Sprintf("%d cloned roles (use --source-profile to check for source changes)", len(entries)))
+	} else if changed > 0 {
+		log.Warning(fmt.Sprintf("%d of %d cloned roles have a changed or missing source", changed, len(entries)))
+	} else {
+		log.Success(fmt.Sprintf("All %d cloned roles match their source", len(entries)))
+	}
+
+	return nil
+}
+
+// collectLineage reads provenance from every role and checks the source when possible
+func collectLineage(ctx context.Context, client, sourceClient *awsclient.Client,
+	sourceAccount, pattern string, log *logger.Logger) ([]LineageEntry, error) {
+
+	roles, err := client.ListRoles(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(roles)
+
+	entries := []LineageEntry{}
+	for _, role := range roles {
+		tags, err := client.GetRoleTags(ctx, role)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Skipping %s: %v", role, err))
+			continue
+		}
+
+		provenance, ok := awsclient.ParseProvenance(tags)
+		if !ok {
+			continue
+		}
+
+		entry := LineageEntry{DestRole: role, Provenance: provenance, SourceStatus: SourceUnchecked}
+		if sourceClient != nil && provenance.SourceAccount == sourceAccount {
+			entry.SourceStatus = sourceStatus(ctx, sourceClient, provenance, log)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Helper function to compare a source role with the hash recorded at clone time
+func sourceStatus(ctx context.Context, sourceClient *awsclient.Client, provenance awsclient.Provenance, log *logger.Logger) string {
+	sourceRole := provenance.SourceRoleName()
+	if !sourceClient.RoleExists(ctx, sourceRole) {
+		return SourceMissing
+	}
+
+	roleInfo, err := sourceClient.GetRoleInfo(ctx, sourceRole)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Could not fetch source %s: %v", sourceRole, err))
+		return SourceUnchecked
+	}
+
+	contentHash, err := awsclient.RoleContentHash(roleInfo)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Could not hash source %s: %v", sourceRole, err))
+		return SourceUnchecked
+	}
+
+	if contentHash != provenance.ContentHash {
+		return SourceChanged
+	}
+	return SourceUnchanged
+}
+
+func printLineage(entries []LineageEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROLE\tSOURCE ROLE\tSOURCE ACCOUNT\tRUN\tVERSION\tSOURCE")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.DestRole, entry.SourceRoleName(), entry.SourceAccount,
+			entry.RunID, entry.ToolVersion, entry.SourceStatus)
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(lineageCmd)
+
+	// Required flags
+	lineageCmd.Flags().StringP("profile", "p", "", "AWS profile with the cloned roles (required)")
+	lineageCmd.MarkFlagRequired("profile")
+
+	// Optional flags
+	lineageCmd.Flags().StringP("source-profile", "s", "", "Source AWS profile to check cloned roles against")
+	lineageCmd.Flags().String("pattern", "", "Only show roles starting with this prefix")
+	lineageCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+	lineageCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+}
@@ -4,11 +4,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	awsclient "iam-role-cloner/internal/aws"
@@ -24,18 +26,54 @@ var listCmd = &cobra.Command{
 This command helps you discover roles before cloning and verify what exists
 in your source and destination accounts.
 
+Roles can be filtered with --pattern (case-insensitive substring) and with the
+selector language of --select, --include and --exclude.
+
 Examples:
   iam-role-cloner list --profile dev                    # List all roles in dev profile
   iam-role-cloner list -p prod --pattern "prod_"        # List roles starting with "prod_"
   iam-role-cloner list --profile staging --details      # List with detailed information
-  iam-role-cloner list -p dev --pattern "app" --sort    # List and sort roles containing "app"`,
+  iam-role-cloner list -p dev --pattern "app" --sort    # List and sort roles containing "app"
+  iam-role-cloner list -p dev --select 'path:/service-role/ and trust:service'
+  iam-role-cloner list -p dev --select 'lastused>90d or lastused:never' --exclude 'tag:Keep'
+
+Output formats (--output):
+  text      Decorated list for reading (default)
+  table     Aligned columns chosen with --columns
+  json      One JSON array with the full role details
+  ndjson    One JSON object per line
+  csv       Columns chosen with --columns, with a header row
+  template  A Go template per role (--template '{{.Name}} {{.Arn}}')
+
+Every format except text writes only data to stdout, so it can be piped into
+jq and scripts; warnings go to stderr.
+
+Details are fetched for --parallel roles at a time. Roles that cannot be read
+because access is denied are listed without details; other failures make the
+command exit with status 1 after printing the rest.
+
+  iam-role-cloner list -p dev -o table --columns name,created,managed,inline
+  iam-role-cloner list -p dev -o ndjson | jq -r 'select(.role.tags.Team == "data") | .name'
+  iam-role-cloner list -p dev --template '{{.Name}}{{"\t"}}{{.Role.Description}}'
+
+Sorting (--sort-by, with --reverse for descending):
+  name, path, created, lastused, maxsession
+Roles never used sort before all others by lastused.
+
+  iam-role-cloner list -p dev --sort-by lastused -o table --columns name,created,lastused`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		profile, _ := cmd.Flags().GetString("profile")
 		pattern, _ := cmd.Flags().GetString("pattern")
 		details, _ := cmd.Flags().GetBool("details")
 		sortRoles, _ := cmd.Flags().GetBool("sort")
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		reverse, _ := cmd.Flags().GetBool("reverse")
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		output, _ := cmd.Flags().GetString("output")
+		columns, _ := cmd.Flags().GetString("columns")
+		templateText, _ := cmd.Flags().GetString("template")
+		parallel, _ := cmd.Flags().GetInt("parallel")
 
 		if profile == "" {
 			fmt.Println("❌ Error: --profile flag is required")
@@ -43,27 +81,62 @@ Examples:
 			return
 		}
 
-		runListCommand(profile, pattern, details, sortRoles, verbose)
+		if parallel < 1 {
+			fmt.Fprintln(os.Stderr, "❌ Error: --parallel must be at least 1")
+			os.Exit(1)
+		}
+
+		if sortRoles && sortBy == "" {
+			sortBy = SortByName
+		}
+		if sortBy != "" && !validSortKey(sortBy) {
+			fmt.Fprintf(os.Stderr, "❌ Error: invalid --sort-by '%s' (use %s)\n", sortBy, strings.Join(listSortKeys, ", "))
+			os.Exit(1)
+		}
+
+		format, err := newListFormat(output, columns, templateText)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		selector, err := buildRoleSelector(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := runListCommand(profile, pattern, selector, format, details, sortBy, reverse, parallel, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
 	},
 }
 
-func runListCommand(profile, pattern string, details, sortRoles, verbose bool) {
+func runListCommand(profile, pattern string, selector *awsclient.RoleSelector, format *listFormat,
+	details bool, sortBy string, reverse bool, parallel int, verbose bool) error {
+
 	// Initialize logger (no file logging for list command)
 	log, err := logger.New(verbose, "")
 	if err != nil {
-		fmt.Printf("Failed to initialize logger: %v\n", err)
-		return
+		return fmt.Errorf("failed to initialize logger: %v", err)
 	}
 	defer log.Close()
 
+	// Keep stdout for data when the output is meant for other programs
+	decorated := format.Decorated()
+	if !decorated {
+		log.SetOutput(os.Stderr)
+		log.SetQuiet(true)
+	}
+
 	log.Header(fmt.Sprintf("📋 IAM Roles in Profile: %s", profile))
 
 	// Create AWS client
 	log.Info(fmt.Sprintf("Connecting to AWS profile: %s", profile))
 	client, err := awsclient.NewClient(profile)
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to create AWS client: %v", err))
-		return
+		return fmt.Errorf("failed to create AWS client: %v", err)
 	}
 
 	ctx := context.Background()
@@ -72,8 +145,7 @@ func runListCommand(profile, pattern string, details, sortRoles, verbose bool) {
 	log.Debug("Validating AWS credentials...")
 	identity, err := client.ValidateCredentials(ctx)
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to validate credentials: %v", err))
-		return
+		return fmt.Errorf("failed to validate credentials: %v", err)
 	}
 
 	log.Success(fmt.Sprintf("Connected to AWS Account: %s", *identity.Account))
@@ -83,90 +155,258 @@ func runListCommand(profile, pattern string, details, sortRoles, verbose bool) {
 	log.Info("Discovering IAM roles...")
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
 	s.Suffix = " Fetching roles..."
-	s.Start()
+	if decorated {
+		s.Start()
+	}
 
-	roles, err := client.ListRoles(ctx, "")
+	summaries, err := client.ListRoleSummaries(ctx, "")
 	s.Stop()
 
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to list roles: %v", err))
-		return
+		return fmt.Errorf("failed to list roles: %v", err)
 	}
 
-	// Filter roles by pattern if specified
-	var filteredRoles []string
+	// Filter roles by pattern and selector if specified
 	if pattern != "" {
 		log.Info(fmt.Sprintf("Filtering roles by pattern: '%s'", pattern))
-		for _, role := range roles {
-			if strings.Contains(strings.ToLower(role), strings.ToLower(pattern)) {
-				filteredRoles = append(filteredRoles, role)
-			}
+		selector.RequireNameContaining(pattern)
+	}
+
+	if !selector.IsEmpty() {
+		if selector.NeedsDetails() {
+			log.Info("Fetching tags and last-used dates for the selector...")
 		}
-		roles = filteredRoles
-		log.Info(fmt.Sprintf("Found %d roles matching pattern", len(roles)))
+		summaries = filterRoleSummaries(ctx, client, summaries, selector, log)
+		log.Info(fmt.Sprintf("Found %d roles matching filters", len(summaries)))
 	} else {
-		log.Info(fmt.Sprintf("Found %d total roles", len(roles)))
+		log.Info(fmt.Sprintf("Found %d total roles", len(summaries)))
 	}
 
-	if len(roles) == 0 {
+	if len(summaries) == 0 && decorated {
 		log.Warning("No roles found")
-		return
+		return nil
 	}
 
-	// Sort roles if requested
-	if sortRoles {
-		log.Debug("Sorting roles alphabetically...")
-		sort.Strings(roles)
+	// Fetch details for all roles up front, concurrently. They carry the
+	// last-used dates, so the roles are not read a second time for those.
+	var results []awsclient.RoleInfoResult
+	if details || format.NeedsDetails() {
+		results = fetchRoleDetails(ctx, client, roleSummaryNames(summaries), parallel, log)
+		applyRoleDetails(summaries, results)
+	} else if sortBy == SortByLastUsed || format.NeedsLastUsed() {
+		// Last-used dates come from GetRole, one call per role. Roles that cannot
+		// be read stay in the list without them.
+		loadRoleDetails(ctx, client, summaries, parallel, "listed without last-used date", log)
+	}
+
+	// Sort roles if requested, keeping the details in the same order
+	if sortBy != "" {
+		log.Debug(fmt.Sprintf("Sorting roles by %s...", sortBy))
+		fetched := roleSummaryNames(summaries)
+		sortRoleSummaries(summaries, sortBy, reverse)
+		if results != nil {
+			results = alignRoleDetails(summaries, fetched, results)
+		}
 	}
 
 	// Display roles
 	log.Separator()
-	if details {
-		displayDetailedRoles(client, roles, log)
-	} else {
-		displaySimpleRoles(roles, pattern, log)
+	switch {
+	case !decorated:
+		listed := collectListedRoles(summaries, results, *identity.Account)
+		if err := format.Write(os.Stdout, listed); err != nil {
+			return fmt.Errorf("failed to write output: %v", err)
+		}
+	case details:
+		displayDetailedRoles(roleSummaryNames(summaries), results, *identity.Account)
+	default:
+		displaySimpleRoles(summaries, pattern, sortBy)
 	}
 
 	// Summary
 	log.Separator()
-	log.Success(fmt.Sprintf("Listed %d roles successfully", len(roles)))
+	failed := reportDetailFailures(roleSummaryNames(summaries), results, log)
+	if failed > 0 {
+		return fmt.Errorf("failed to get details for %d role(s)", failed)
+	}
+	log.Success(fmt.Sprintf("Listed %d roles successfully", len(summaries)))
+
+	return nil
+}
+
+// fetchRoleDetails gets the details of the roles concurrently, with at most
+// parallel roles in flight, behind one progress bar
+func fetchRoleDetails(ctx context.Context, client *awsclient.Client, roleNames []string,
+	parallel int, log *logger.Logger) []awsclient.RoleInfoResult {
+
+	log.Debug(fmt.Sprintf("Getting details for %d roles (%d in parallel)", len(roleNames), parallel))
+
+	bar := newProgressBar("Getting role details", len(roleNames))
+	results := client.GetRoleInfos(ctx, roleNames, parallel, bar.Increment)
+	bar.Finish()
+
+	return results
+}
+
+// applyRoleDetails copies the tags and last-used dates of the fetched details
+// onto the summaries, which are in the same order as results
+func applyRoleDetails(summaries []*awsclient.RoleSummary, results []awsclient.RoleInfoResult) {
+	for i, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		summaries[i].Tags = result.Info.Tags
+		summaries[i].LastUsed, summaries[i].LastUsedRegion = result.Info.LastUsed, result.Info.LastUsedRegion
+		summaries[i].DetailsLoaded = true
+	}
+}
+
+// alignRoleDetails reorders results, which are in the order of roleNames, to
+// follow the order of summaries after they were sorted. Role names are unique.
+func alignRoleDetails(summaries []*awsclient.RoleSummary, roleNames []string,
+	results []awsclient.RoleInfoResult) []awsclient.RoleInfoResult {
+
+	byName := make(map[string]awsclient.RoleInfoResult, len(results))
+	for i, result := range results {
+		byName[roleNames[i]] = result
+	}
+
+	aligned := make([]awsclient.RoleInfoResult, 0, len(summaries))
+	for _, summary := range summaries {
+		aligned = append(aligned, byName[summary.Name])
+	}
+	return aligned
+}
+
+// reportDetailFailures warns about roles whose details could not be read. Roles
+// the caller may not read are expected in partial results; it returns the number
+// of roles that failed for any other reason.
+func reportDetailFailures(roleNames []string, results []awsclient.RoleInfoResult, log *logger.Logger) int {
+	var denied []string
+	failed := 0
+
+	for i, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		log.Debug(fmt.Sprintf("Could not get details for %s: %v", roleNames[i], result.Err))
+		if awsclient.IsAccessDenied(result.Err) {
+			denied = append(denied, roleNames[i])
+		} else {
+			failed++
+		}
+	}
+
+	if len(denied) > 0 {
+		log.Warning(fmt.Sprintf("Access denied to the details of %d role(s); listed without them: %s",
+			len(denied), strings.Join(denied, ", ")))
+	}
+	if failed > 0 {
+		log.Error(fmt.Sprintf("Could not get details for %d role(s) (use --verbose for the errors)", failed))
+	}
+
+	return failed
 }
 
-func displaySimpleRoles(roles []string, pattern string, log *logger.Logger) {
+// collectListedRoles prepares roles for the data formats, with the details in
+// results when they were fetched. Roles whose details failed keep their error.
+func collectListedRoles(summaries []*awsclient.RoleSummary, results []awsclient.RoleInfoResult,
+	accountID string) []*ListedRole {
+
+	listed := make([]*ListedRole, 0, len(summaries))
+	for i, summary := range summaries {
+		role := &ListedRole{
+			Name:               summary.Name,
+			Path:               summary.Path,
+			Arn:                summary.Arn,
+			CreateDate:         summary.CreateDate,
+			MaxSessionDuration: summary.MaxSessionDuration,
+		}
+		if summary.DetailsLoaded {
+			role.LastUsed, role.LastUsedRegion = summary.LastUsed, summary.LastUsedRegion
+			role.LastUsedLoaded = true
+		}
+
+		if trust, err := awsclient.ParseTrustPolicy(summary.TrustPolicy); err == nil {
+			role.Trust = trust.Classify(accountID)
+		}
+
+		if results != nil {
+			if results[i].Err != nil {
+				role.Error = results[i].Err.Error()
+			} else {
+				role.Role = results[i].Info
+				role.LastUsed, role.LastUsedRegion = role.Role.LastUsed, role.Role.LastUsedRegion
+				role.LastUsedLoaded = true
+			}
+		}
+
+		listed = append(listed, role)
+	}
+
+	return listed
+}
+
+func displaySimpleRoles(roles []*awsclient.RoleSummary, pattern, sortBy string) {
 	fmt.Printf("\n📝 Role Names:\n")
 	fmt.Println("=" + strings.Repeat("=", 50))
 
 	for i, role := range roles {
-		// Highlight the pattern if specified
-		displayName := role
-		if pattern != "" {
-			displayName = strings.ReplaceAll(role, pattern, fmt.Sprintf("**%s**", pattern))
+		line := fmt.Sprintf("%3d. %s", i+1, highlightPattern(role.Name, pattern))
+
+		// Show the value the roles are sorted by
+		switch sortBy {
+		case SortByPath:
+			line += fmt.Sprintf("  (%s)", role.Path)
+		case SortByCreated:
+			line += fmt.Sprintf("  (created %s)", role.CreateDate.Format("2006-01-02"))
+		case SortByLastUsed:
+			line += fmt.Sprintf("  (last used %s)", formatLastUsed(role.LastUsed))
+		case SortByMaxSession:
+			line += fmt.Sprintf("  (max session %s)", formatSessionDuration(role.MaxSessionDuration))
 		}
 
-		fmt.Printf("%3d. %s\n", i+1, displayName)
+		fmt.Println(line)
 	}
 }
 
-func displayDetailedRoles(client *awsclient.Client, roles []string, log *logger.Logger) {
+// highlightPattern shows case-insensitive occurrences of pattern in bold. Colors
+// are turned off automatically when stdout is not a terminal.
+func highlightPattern(name, pattern string) string {
+	if pattern == "" {
+		return name
+	}
+
+	bold := color.New(color.Bold)
+	lowerName, lowerPattern := strings.ToLower(name), strings.ToLower(pattern)
+
+	var b strings.Builder
+	for {
+		index := strings.Index(lowerName, lowerPattern)
+		if index < 0 {
+			b.WriteString(name)
+			return b.String()
+		}
+		end := index + len(pattern)
+		b.WriteString(name[:index])
+		b.WriteString(bold.Sprint(name[index:end]))
+		name, lowerName = name[end:], lowerName[end:]
+	}
+}
+
+func displayDetailedRoles(roles []string, results []awsclient.RoleInfoResult, accountID string) {
 	fmt.Printf("\n📋 Detailed Role Information:\n")
 	fmt.Println("=" + strings.Repeat("=", 80))
 
-	ctx := context.Background()
-
 	for i, roleName := range roles {
 		fmt.Printf("\n[%d/%d] %s\n", i+1, len(roles), roleName)
 		fmt.Println(strings.Repeat("-", len(roleName)+10))
 
-		// Get detailed role information
-		log.Debug(fmt.Sprintf("Getting details for role: %s", roleName))
-
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Suffix = fmt.Sprintf(" Getting details for %s...", roleName)
-		s.Start()
-
-		roleInfo, err := client.GetRoleInfo(ctx, roleName)
-		s.Stop()
-
+		roleInfo, err := results[i].Info, results[i].Err
+		if awsclient.IsAccessDenied(err) {
+			fmt.Printf("🚫 Details unavailable: access denied\n")
+			continue
+		}
 		if err != nil {
 			fmt.Printf("❌ Error getting role details: %v\n", err)
 			continue
@@ -174,6 +414,15 @@ func displayDetailedRoles(client *awsclient.Client, roles []string, log *logger.
 
 		// Display role details
 		fmt.Printf("📝 Description: %s\n", getDescription(roleInfo.Description))
+		fmt.Printf("📁 Path: %s\n", roleInfo.Path)
+		fmt.Printf("🆔 ARN: %s\n", roleInfo.Arn)
+		fmt.Printf("📅 Created: %s\n", roleInfo.CreateDate.Format("2006-01-02"))
+		lastUsed := formatLastUsed(roleInfo.LastUsed)
+		if roleInfo.LastUsedRegion != "" {
+			lastUsed += fmt.Sprintf(" (%s)", roleInfo.LastUsedRegion)
+		}
+		fmt.Printf("🕒 Last used: %s\n", lastUsed)
+		fmt.Printf("⏱️  Max session: %s\n", formatSessionDuration(roleInfo.MaxSessionDuration))
 		fmt.Printf("🔒 Managed Policies: %d\n", len(roleInfo.ManagedPolicies))
 
 		if len(roleInfo.ManagedPolicies) > 0 && len(roleInfo.ManagedPolicies) <= 5 {
@@ -209,16 +458,125 @@ func displayDetailedRoles(client *awsclient.Client, roles []string, log *logger.
 		}
 
 		// Show trust relationship summary
-		if strings.Contains(roleInfo.TrustPolicy, "ec2.amazonaws.com") {
-			fmt.Printf("🖥️  Trust: EC2 Service Role\n")
-		} else if strings.Contains(roleInfo.TrustPolicy, "lambda.amazonaws.com") {
-			fmt.Printf("🚀 Trust: Lambda Service Role\n")
-		} else if strings.Contains(roleInfo.TrustPolicy, "sts:AssumeRole") {
-			fmt.Printf("👤 Trust: Cross-Account Role\n")
-		} else {
-			fmt.Printf("🔗 Trust: Custom Trust Policy\n")
+		trust, err := awsclient.ParseTrustPolicy(roleInfo.TrustPolicy)
+		if err != nil {
+			fmt.Printf("🔗 Trust: Unparseable Trust Policy (%v)\n", err)
+			continue
 		}
+		fmt.Printf("%s Trust: %s\n", trustIcon(trust), trust.Classify(accountID))
+		if actions := trust.AssumeActions(); len(actions) > 0 {
+			fmt.Printf("    • Actions: %s\n", strings.Join(actions, ", "))
+		}
+		if services := trust.Principals(awsclient.PrincipalService); len(services) > 1 {
+			fmt.Printf("    • Services: %s\n", strings.Join(services, ", "))
+		}
+		for _, account := range trust.Accounts() {
+			if account != accountID {
+				fmt.Printf("    • Trusted account: %s\n", account)
+			}
+		}
+	}
+}
+
+// Keys of --sort-by
+const (
+	SortByName       = "name"
+	SortByPath       = "path"
+	SortByCreated    = "created"
+	SortByLastUsed   = "lastused"
+	SortByMaxSession = "maxsession"
+)
+
+var listSortKeys = []string{SortByName, SortByPath, SortByCreated, SortByLastUsed, SortByMaxSession}
+
+// Helper function to check a --sort-by value
+func validSortKey(key string) bool {
+	for _, k := range listSortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// sortRoleSummaries sorts roles by a --sort-by key, then by name. By last use,
+// roles never used come first, as the least recently used.
+func sortRoleSummaries(roles []*awsclient.RoleSummary, key string, reverse bool) {
+	less := func(a, b *awsclient.RoleSummary) bool {
+		switch key {
+		case SortByPath:
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+		case SortByCreated:
+			if !a.CreateDate.Equal(b.CreateDate) {
+				return a.CreateDate.Before(b.CreateDate)
+			}
+		case SortByLastUsed:
+			switch {
+			case a.LastUsed == nil && b.LastUsed != nil:
+				return true
+			case a.LastUsed != nil && b.LastUsed == nil:
+				return false
+			case a.LastUsed != nil && !a.LastUsed.Equal(*b.LastUsed):
+				return a.LastUsed.Before(*b.LastUsed)
+			}
+		case SortByMaxSession:
+			if a.MaxSessionDuration != b.MaxSessionDuration {
+				return a.MaxSessionDuration < b.MaxSessionDuration
+			}
+		}
+		return a.Name < b.Name
+	}
+
+	sort.SliceStable(roles, func(i, j int) bool {
+		if reverse {
+			return less(roles[j], roles[i])
+		}
+		return less(roles[i], roles[j])
+	})
+}
+
+// formatLastUsed shows a last-used date, or "never"
+func formatLastUsed(lastUsed *time.Time) string {
+	if lastUsed == nil {
+		return "never"
+	}
+	return lastUsed.Format("2006-01-02")
+}
+
+// formatSessionDuration shows a maximum session duration in seconds as hours or minutes
+func formatSessionDuration(seconds int32) string {
+	switch {
+	case seconds == 0:
+		return ""
+	case seconds%3600 == 0:
+		return fmt.Sprintf("%dh", seconds/3600)
+	case seconds%60 == 0:
+		return fmt.Sprintf("%dm", seconds/60)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// trustIcon picks an icon for the dominant kind of principal in a trust policy
+func trustIcon(trust *awsclient.TrustPolicy) string {
+	services := trust.Principals(awsclient.PrincipalService)
+
+	switch {
+	case trust.HasWildcardPrincipal():
+		return "⚠️ "
+	case len(services) == 1 && services[0] == "ec2.amazonaws.com":
+		return "🖥️ "
+	case len(services) == 1 && services[0] == "lambda.amazonaws.com":
+		return "🚀"
+	case len(services) > 0:
+		return "⚙️ "
+	case len(trust.Principals(awsclient.PrincipalFederated)) > 0:
+		return "🌐"
+	case len(trust.Accounts()) > 0:
+		return "👤"
 	}
+	return "🔗"
 }
 
 func getDescription(desc string) string {
@@ -249,6 +607,13 @@ func init() {
 	// Optional flags
 	listCmd.Flags().String("pattern", "", "Filter roles by pattern (case-insensitive)")
 	listCmd.Flags().Bool("details", false, "Show detailed information for each role")
-	listCmd.Flags().Bool("sort", false, "Sort roles alphabetically")
+	listCmd.Flags().Bool("sort", false, "Sort roles alphabetically (same as --sort-by name)")
+	listCmd.Flags().String("sort-by", "", "Sort roles by name, path, created, lastused or maxsession")
+	listCmd.Flags().Bool("reverse", false, "Reverse the sort order")
 	listCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	listCmd.Flags().StringP("output", "o", ListText, "Output format: text, table, json, ndjson, csv or template")
+	listCmd.Flags().String("columns", "", "Comma-separated columns for table and csv (name, path, arn, created, lastused, maxsession, trust, description, managed, inline, tags)")
+	listCmd.Flags().Int("parallel", defaultParallelism, "Roles to fetch details for at the same time")
+	listCmd.Flags().String("template", "", "Go template rendered for each role (implies --output template)")
+	addSelectorFlags(listCmd)
 }
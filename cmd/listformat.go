@@ -0,0 +1,273 @@
+// cmd/listformat.go - Output formats of the list command
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	awsclient "iam-role-cloner/internal/aws"
+)
+
+// Output formats of the list command
+const (
+	ListText     = "text"
+	ListTable    = "table"
+	ListJSON     = "json"
+	ListNDJSON   = "ndjson"
+	ListCSV      = "csv"
+	ListTemplate = "template"
+)
+
+// ListedRole is one role in the table, JSON, CSV and template output of list
+type ListedRole struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Arn        string    `json:"arn"`
+	CreateDate time.Time `json:"create_date"`
+	// MaxSessionDuration is in seconds
+	MaxSessionDuration int32  `json:"max_session_duration"`
+	Trust              string `json:"trust"`
+	// LastUsed is nil when the role was never used or the date was not fetched
+	LastUsed       *time.Time          `json:"last_used,omitempty"`
+	LastUsedRegion string              `json:"last_used_region,omitempty"`
+	LastUsedLoaded bool                `json:"-"`
+	Role           *awsclient.RoleInfo `json:"role,omitempty"`
+	// Error is set when the role's details could not be fetched
+	Error string `json:"error,omitempty"`
+}
+
+// listColumns renders the columns of the table and csv formats
+var listColumns = map[string]func(role *ListedRole) string{
+	"name":    func(role *ListedRole) string { return role.Name },
+	"path":    func(role *ListedRole) string { return role.Path },
+	"arn":     func(role *ListedRole) string { return role.Arn },
+	"created": func(role *ListedRole) string { return role.CreateDate.Format("2006-01-02") },
+	"trust":   func(role *ListedRole) string { return role.Trust },
+	"maxsession": func(role *ListedRole) string {
+		return formatSessionDuration(role.MaxSessionDuration)
+	},
+	"lastused": func(role *ListedRole) string {
+		if !role.LastUsedLoaded {
+			return ""
+		}
+		return formatLastUsed(role.LastUsed)
+	},
+	"description": func(role *ListedRole) string {
+		if role.Role == nil {
+			return ""
+		}
+		return role.Role.Description
+	},
+	"managed": func(role *ListedRole) string {
+		if role.Role == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", len(role.Role.ManagedPolicies))
+	},
+	"inline": func(role *ListedRole) string {
+		if role.Role == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", len(role.Role.InlinePolicies))
+	},
+	"tags": func(role *ListedRole) string {
+		if role.Role == nil {
+			return ""
+		}
+		pairs := make([]string, 0, len(role.Role.Tags))
+		for _, key := range awsclient.SortedKeys(role.Role.Tags) {
+			pairs = append(pairs, key+"="+role.Role.Tags[key])
+		}
+		return strings.Join(pairs, ";")
+	},
+}
+
+// Columns that need the role's policies, description or tags
+var detailColumns = map[string]bool{"description": true, "managed": true, "inline": true, "tags": true}
+
+// Default columns of the table and csv formats
+const (
+	defaultTableColumns = "name,path,created,maxsession,trust"
+	defaultCSVColumns   = "name,path,arn,created,maxsession,trust"
+)
+
+// listFormat is the output format chosen with --output, --columns and --template
+type listFormat struct {
+	Name         string
+	Columns      []string
+	Template     *template.Template
+	templateText string
+}
+
+// newListFormat validates the output flags of list
+func newListFormat(name, columns, templateText string) (*listFormat, error) {
+	if templateText != "" && name == ListText {
+		name = ListTemplate
+	}
+
+	format := &listFormat{Name: name, templateText: templateText}
+
+	switch name {
+	case ListText, ListJSON, ListNDJSON:
+	case ListTable, ListCSV:
+		if columns == "" {
+			columns = defaultTableColumns
+			if name == ListCSV {
+				columns = defaultCSVColumns
+			}
+		}
+		for _, column := range strings.Split(columns, ",") {
+			column = strings.ToLower(strings.TrimSpace(column))
+			if _, ok := listColumns[column]; !ok {
+				return nil, fmt.Errorf("unknown column '%s' (available: %s)", column, strings.Join(listColumnNames(), ", "))
+			}
+			format.Columns = append(format.Columns, column)
+		}
+	case ListTemplate:
+		if templateText == "" {
+			return nil, fmt.Errorf("--output template needs --template")
+		}
+		tmpl, err := template.New("list").Funcs(template.FuncMap{
+			"join": strings.Join,
+			"json": func(value interface{}) (string, error) {
+				data, err := json.Marshal(value)
+				return string(data), err
+			},
+		}).Parse(templateText)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %v", err)
+		}
+		format.Template = tmpl
+	default:
+		return nil, fmt.Errorf("unsupported output format '%s' (use text, table, json, ndjson, csv or template)", name)
+	}
+
+	if columns != "" && format.Columns == nil {
+		return nil, fmt.Errorf("--columns only applies to the table and csv formats")
+	}
+
+	return format, nil
+}
+
+// Decorated reports whether the format is the human-oriented text with headers
+// and progress; every other format writes only data to stdout
+func (f *listFormat) Decorated() bool {
+	return f.Name == ListText
+}
+
+// NeedsDetails reports whether the format shows more than ListRoles returns
+func (f *listFormat) NeedsDetails() bool {
+	switch f.Name {
+	case ListJSON, ListNDJSON:
+		return true
+	case ListTemplate:
+		return strings.Contains(f.templateText, ".Role")
+	}
+	for _, column := range f.Columns {
+		if detailColumns[column] {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsLastUsed reports whether the format shows last-used dates, which only GetRole returns
+func (f *listFormat) NeedsLastUsed() bool {
+	if f.Name == ListTemplate {
+		return strings.Contains(f.templateText, ".LastUsed")
+	}
+	for _, column := range f.Columns {
+		if column == "lastused" {
+			return true
+		}
+	}
+	return false
+}
+
+// Write renders the roles in the format
+func (f *listFormat) Write(w io.Writer, roles []*ListedRole) error {
+	switch f.Name {
+	case ListJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(roles)
+
+	case ListNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, role := range roles {
+			if err := encoder.Encode(role); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case ListCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(f.Columns); err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if err := writer.Write(f.row(role)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case ListTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(f.Columns, "\t")))
+		for _, role := range roles {
+			fmt.Fprintln(tw, strings.Join(f.row(role), "\t"))
+		}
+		return tw.Flush()
+
+	case ListTemplate:
+		for _, role := range roles {
+			var out strings.Builder
+			if err := f.Template.Execute(&out, role); err != nil {
+				// Roles without details are already reported; keep the rest of the output
+				if role.Error != "" {
+					continue
+				}
+				return fmt.Errorf("failed to render %s: %v", role.Name, err)
+			}
+			text := out.String()
+			if !strings.HasSuffix(text, "\n") {
+				text += "\n"
+			}
+			if _, err := io.WriteString(w, text); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("format %s has no data output", f.Name)
+}
+
+// Helper function to render the chosen columns of a role
+func (f *listFormat) row(role *ListedRole) []string {
+	values := make([]string, len(f.Columns))
+	for i, column := range f.Columns {
+		values[i] = listColumns[column](role)
+	}
+	return values
+}
+
+// Helper function to list the column names in order
+func listColumnNames() []string {
+	names := make([]string, 0, len(listColumns))
+	for name := range listColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
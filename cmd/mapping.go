@@ -0,0 +1,75 @@
+// cmd/mapping.go - Clone roles listed in a mapping file
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// destRoleName returns the destination name of a source role: a name chosen during
+// collision handling, the mapping file entry, or the pattern replacement
+func destRoleName(config *CloneConfig, sourceRole string) string {
+	if name, ok := config.DestNames[sourceRole]; ok {
+		return name
+	}
+	if row := config.Mapping.Row(sourceRole); row != nil {
+		return row.Destination
+	}
+	return awsclient.GenerateNewRoleName(sourceRole, config.SourcePattern, config.DestPattern)
+}
+
+// selectMappedRoles takes the roles from the mapping file after checking that every
+// source exists. Taken destination names are left to the collision analysis.
+func selectMappedRoles(config *CloneConfig, log *logger.Logger) error {
+	log.Info(fmt.Sprintf("Using mapping file: %s (%d roles)", config.Mapping.Path, len(config.Mapping.Rows)))
+
+	sourceClient, err := awsclient.NewClient(config.SourceProfile)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	sourceRoles, err := sourceClient.ListRoleSummaries(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list source roles: %v", err)
+	}
+
+	bySource := make(map[string]*awsclient.RoleSummary, len(sourceRoles))
+	for _, role := range sourceRoles {
+		bySource[role.Name] = role
+	}
+
+	var problems []string
+	var mapped []*awsclient.RoleSummary
+	for _, row := range config.Mapping.Rows {
+		role, ok := bySource[row.Source]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("entry %d: source role %s not found", row.Line, row.Source))
+			continue
+		}
+		mapped = append(mapped, role)
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Error(problem)
+		}
+		return fmt.Errorf("mapping file has %d problem(s), nothing was cloned", len(problems))
+	}
+
+	if !config.Selector.IsEmpty() {
+		log.Info("Applying role selector to the mapped roles...")
+		mapped = filterRoleSummaries(ctx, sourceClient, mapped, config.Selector, log)
+	}
+
+	config.Roles = roleSummaryNames(mapped)
+	for i, role := range config.Roles {
+		fmt.Printf("  %d. %s → %s\n", i+1, role, destRoleName(config, role))
+	}
+	log.Success(fmt.Sprintf("Selected %d mapped roles", len(config.Roles)))
+
+	return nil
+}
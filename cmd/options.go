@@ -0,0 +1,228 @@
+// cmd/options.go - Transformation options shared by clone and drift
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/mapping"
+)
+
+// cloneOptions are the flag values that decide how a source role is transformed.
+// Clone records them in its journal so drift can recompute the same destination state.
+type cloneOptions struct {
+	SourcePattern string `json:"source_pattern,omitempty"`
+	DestPattern   string `json:"dest_pattern,omitempty"`
+
+	EKSOIDCMap            map[string]string `json:"eks_oidc_map,omitempty"`
+	IRSANamespaceMap      map[string]string `json:"irsa_namespace_map,omitempty"`
+	IRSAServiceAccountMap map[string]string `json:"irsa_service_account_map,omitempty"`
+	GitHubRepoMap         map[string]string `json:"github_repo_map,omitempty"`
+	GitHubEnvMap          map[string]string `json:"github_env_map,omitempty"`
+	GitHubBranchMap       map[string]string `json:"github_branch_map,omitempty"`
+
+	MaxTrustPolicySize  int    `json:"max_trust_policy_size"`
+	MaxManagedPolicies  int    `json:"max_managed_policies"`
+	MinifyPolicies      bool   `json:"minify_policies,omitempty"`
+	OversizeInline      string `json:"oversize_inline"`
+	ShareInlinePolicies bool   `json:"share_inline_policies,omitempty"`
+
+	// Mapping is the absolute path of the mapping file
+	Mapping string `json:"mapping,omitempty"`
+
+	TagSet            map[string]string `json:"tag_set,omitempty"`
+	TagDelete         []string          `json:"tag_delete,omitempty"`
+	TagRename         map[string]string `json:"tag_rename,omitempty"`
+	TagReplace        []string          `json:"tag_replace,omitempty"`
+	TagAllow          []string          `json:"tag_allow,omitempty"`
+	NoDefaultTagRules bool              `json:"no_default_tag_rules,omitempty"`
+
+	Provenance        bool   `json:"provenance,omitempty"`
+	DescriptionPrefix string `json:"description_prefix,omitempty"`
+	DescriptionSuffix string `json:"description_suffix,omitempty"`
+}
+
+// addCloneOptionFlags registers the flags read by readCloneOptions
+func addCloneOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("source-pattern", "", "Source environment pattern (e.g., 'dev_')")
+	cmd.Flags().String("dest-pattern", "", "Destination environment pattern (e.g., 'prod_')")
+	cmd.Flags().StringToString("eks-oidc-map", nil, "Map EKS cluster OIDC IDs or provider hosts (e.g., 'ABC123=DEF456')")
+	cmd.Flags().StringToString("irsa-namespace-map", nil, "Map Kubernetes namespaces in IRSA trust conditions (e.g., 'dev=prod')")
+	cmd.Flags().StringToString("irsa-service-account-map", nil, "Map service accounts in IRSA trust conditions (e.g., 'app-dev=app' or 'ns:sa=ns:sa')")
+	cmd.Flags().StringToString("github-repo-map", nil, "Map GitHub repos or owners in Actions trust conditions (e.g., 'org/app-dev=org/app')")
+	cmd.Flags().StringToString("github-env-map", nil, "Map GitHub environments in Actions trust conditions (e.g., 'dev=prod')")
+	cmd.Flags().StringToString("github-branch-map", nil, "Map GitHub branches in Actions trust conditions (e.g., 'develop=main')")
+	cmd.Flags().Int("max-trust-policy-size", awsclient.DefaultIAMLimits().TrustPolicySize, "Trust policy size quota for the destination account")
+	cmd.Flags().Int("max-managed-policies", awsclient.DefaultIAMLimits().ManagedPoliciesPerRole, "Managed policies per role quota for the destination account")
+	cmd.Flags().Bool("minify-policies", false, "Remove whitespace from policy documents before upload")
+	cmd.Flags().String("oversize-inline", OversizeFail, "How to handle inline policies over the IAM size quota: fail or managed (splitting into more inline policies cannot help; the 10,240-character quota covers all inline policies of a role combined)")
+	cmd.Flags().Bool("share-inline-policies", false, "Create one customer-managed policy per distinct inline document and attach it instead")
+	cmd.Flags().String("mapping", "", "CSV or YAML file mapping source roles to destination names, with optional per-role overrides")
+	cmd.Flags().StringToString("tag-set", nil, "Set or override tags; values may use templates (e.g., 'ClonedFrom={{.SourceRoleArn}}')")
+	cmd.Flags().StringSlice("tag-delete", nil, "Remove tags by key ('*' removes all source tags)")
+	cmd.Flags().StringToString("tag-rename", nil, "Rename tag keys, applied in order of the old key (e.g., 'Env=Environment')")
+	cmd.Flags().StringArray("tag-replace", nil, "Regex-replace tag values: 'KEY=REGEX=>REPLACEMENT' ('*' for all keys)")
+	cmd.Flags().StringSlice("tag-allow", nil, "Copy only these source tag keys")
+	cmd.Flags().Bool("no-default-tag-rules", false, "Keep the Environment tag instead of setting it to the destination environment")
+	cmd.Flags().Bool("provenance", false, "Tag cloned roles with source account, source role ARN, content hash, tool version and run ID")
+	cmd.Flags().String("description-prefix", "", "Template prepended to the source description (e.g., '[{{.SourceEnvironment}}→{{.DestEnvironment}}] ')")
+	cmd.Flags().String("description-suffix", "", "Template appended to the source description (e.g., ' (cloned {{.CloneDate}})')")
+}
+
+// readCloneOptions reads the flags registered by addCloneOptionFlags
+func readCloneOptions(cmd *cobra.Command) (cloneOptions, error) {
+	var opts cloneOptions
+	opts.SourcePattern, _ = cmd.Flags().GetString("source-pattern")
+	opts.DestPattern, _ = cmd.Flags().GetString("dest-pattern")
+	opts.EKSOIDCMap, _ = cmd.Flags().GetStringToString("eks-oidc-map")
+	opts.IRSANamespaceMap, _ = cmd.Flags().GetStringToString("irsa-namespace-map")
+	opts.IRSAServiceAccountMap, _ = cmd.Flags().GetStringToString("irsa-service-account-map")
+	opts.GitHubRepoMap, _ = cmd.Flags().GetStringToString("github-repo-map")
+	opts.GitHubEnvMap, _ = cmd.Flags().GetStringToString("github-env-map")
+	opts.GitHubBranchMap, _ = cmd.Flags().GetStringToString("github-branch-map")
+	opts.MaxTrustPolicySize, _ = cmd.Flags().GetInt("max-trust-policy-size")
+	opts.MaxManagedPolicies, _ = cmd.Flags().GetInt("max-managed-policies")
+	opts.MinifyPolicies, _ = cmd.Flags().GetBool("minify-policies")
+	opts.OversizeInline, _ = cmd.Flags().GetString("oversize-inline")
+	opts.ShareInlinePolicies, _ = cmd.Flags().GetBool("share-inline-policies")
+	opts.Mapping, _ = cmd.Flags().GetString("mapping")
+	opts.TagSet, _ = cmd.Flags().GetStringToString("tag-set")
+	opts.TagDelete, _ = cmd.Flags().GetStringSlice("tag-delete")
+	opts.TagRename, _ = cmd.Flags().GetStringToString("tag-rename")
+	opts.TagReplace, _ = cmd.Flags().GetStringArray("tag-replace")
+	opts.TagAllow, _ = cmd.Flags().GetStringSlice("tag-allow")
+	opts.NoDefaultTagRules, _ = cmd.Flags().GetBool("no-default-tag-rules")
+	opts.Provenance, _ = cmd.Flags().GetBool("provenance")
+	opts.DescriptionPrefix, _ = cmd.Flags().GetString("description-prefix")
+	opts.DescriptionSuffix, _ = cmd.Flags().GetString("description-suffix")
+
+	// The journal outlives the working directory of the run
+	if opts.Mapping != "" {
+		path, err := filepath.Abs(opts.Mapping)
+		if err != nil {
+			return opts, fmt.Errorf("failed to resolve mapping file: %v", err)
+		}
+		opts.Mapping = path
+	}
+
+	return opts, nil
+}
+
+// newCloneConfig validates the options and builds the transformation part of a CloneConfig
+func newCloneConfig(opts cloneOptions, startedAt time.Time) (*CloneConfig, error) {
+	if opts.OversizeInline == "split" {
+		return nil, fmt.Errorf("--oversize-inline split cannot help: the 10,240-character quota covers all inline policies of a role combined (use %s)", OversizeManaged)
+	}
+	if opts.OversizeInline != OversizeFail && opts.OversizeInline != OversizeManaged {
+		return nil, fmt.Errorf("invalid --oversize-inline '%s' (use %s or %s)",
+			opts.OversizeInline, OversizeFail, OversizeManaged)
+	}
+
+	tagRules, err := buildTagRules(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var roleMapping *mapping.File
+	if opts.Mapping != "" {
+		roleMapping, err = mapping.Load(opts.Mapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prefixTemplate, err := awsclient.ParseTagTemplate("description-prefix", opts.DescriptionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	suffixTemplate, err := awsclient.ParseTagTemplate("description-suffix", opts.DescriptionSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := awsclient.DefaultIAMLimits()
+	limits.TrustPolicySize = opts.MaxTrustPolicySize
+	limits.ManagedPoliciesPerRole = opts.MaxManagedPolicies
+
+	return &CloneConfig{
+		SourcePattern: opts.SourcePattern,
+		DestPattern:   opts.DestPattern,
+		IRSA: awsclient.IRSAMapping{
+			Providers:       opts.EKSOIDCMap,
+			Namespaces:      opts.IRSANamespaceMap,
+			ServiceAccounts: opts.IRSAServiceAccountMap,
+		},
+		GitHub: awsclient.GitHubMapping{
+			Repos:        opts.GitHubRepoMap,
+			Environments: opts.GitHubEnvMap,
+			Branches:     opts.GitHubBranchMap,
+		},
+		Limits:              limits,
+		MinifyPolicies:      opts.MinifyPolicies,
+		OversizeInline:      opts.OversizeInline,
+		ShareInlinePolicies: opts.ShareInlinePolicies,
+		TagRules:            tagRules,
+		StartedAt:           startedAt,
+		Provenance:          opts.Provenance,
+		DescriptionPrefix:   prefixTemplate,
+		DescriptionSuffix:   suffixTemplate,
+		Mapping:             roleMapping,
+		Options:             opts,
+	}, nil
+}
+
+// buildTagRules turns the tag options into rules. The default rule sets an
+// existing Environment tag to the destination environment.
+func buildTagRules(opts cloneOptions) (awsclient.TagRules, error) {
+	var rules awsclient.TagRules
+
+	if !opts.NoDefaultTagRules {
+		// Keep the value when there is no destination environment, e.g. with --mapping alone
+		rule, err := awsclient.NewReplaceRule("Environment", "^.*$", "{{if .DestEnvironment}}{{.DestEnvironment}}{{else}}$0{{end}}")
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, key := range opts.TagAllow {
+		rules = append(rules, awsclient.NewAllowRule(key))
+	}
+	for _, key := range opts.TagDelete {
+		rules = append(rules, awsclient.NewDeleteRule(key))
+	}
+	// Sorted so chained renames (A=B, B=C) give the same result on every run
+	for _, key := range awsclient.SortedKeys(opts.TagRename) {
+		rules = append(rules, awsclient.NewRenameRule(key, opts.TagRename[key]))
+	}
+	for _, spec := range opts.TagReplace {
+		rule, err := awsclient.ParseReplaceRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	for key, value := range opts.TagSet {
+		rule, err := awsclient.NewSetRule(key, value)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Helper function to encode the options for the journal run entry
+func (o cloneOptions) encode() (json.RawMessage, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode clone options: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,87 @@
+// cmd/picker.go - Full-screen role picker for clone
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+	"iam-role-cloner/internal/picker"
+)
+
+// pickRoles lets the user choose roles in the full-screen picker
+func pickRoles(ctx context.Context, client *awsclient.Client, config *CloneConfig,
+	roles []*awsclient.RoleSummary, log *logger.Logger) error {
+
+	items := make([]picker.Item, len(roles))
+	for i, role := range roles {
+		role := role
+		items[i] = picker.Item{
+			Label: role.Name,
+			Preview: func() []string {
+				return rolePreview(ctx, client, config, role)
+			},
+		}
+	}
+
+	title := fmt.Sprintf("Select roles to clone (%s → %s)", config.SourceProfile, config.DestProfile)
+	indexes, err := picker.Run(title, items)
+	if err != nil {
+		return err
+	}
+
+	config.Roles = make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		config.Roles = append(config.Roles, roles[index].Name)
+	}
+	log.Success(fmt.Sprintf("Selected %d roles", len(config.Roles)))
+
+	return nil
+}
+
+// rolePreview describes a role for the picker preview pane
+func rolePreview(ctx context.Context, client *awsclient.Client, config *CloneConfig, role *awsclient.RoleSummary) []string {
+	lines := []string{
+		fmt.Sprintf("Source:       %s", role.Name),
+		fmt.Sprintf("Destination:  %s", destRoleName(config, role.Name)),
+		fmt.Sprintf("Path:         %s", role.Path),
+		fmt.Sprintf("Created:      %s", role.CreateDate.Format("2006-01-02")),
+	}
+	if role.DetailsLoaded {
+		lastUsed := "never"
+		if role.LastUsed != nil {
+			lastUsed = role.LastUsed.Format("2006-01-02")
+		}
+		lines = append(lines, fmt.Sprintf("Last used:    %s", lastUsed))
+	}
+
+	lines = append(lines, "")
+	trust, err := awsclient.ParseTrustPolicy(role.TrustPolicy)
+	if err != nil {
+		lines = append(lines, fmt.Sprintf("Trust:        unparseable (%v)", err))
+	} else {
+		lines = append(lines, fmt.Sprintf("Trust:        %s", trust.Classify(config.SourceAccount)))
+		for _, principalType := range []string{awsclient.PrincipalService, awsclient.PrincipalAWS, awsclient.PrincipalFederated} {
+			for _, principal := range trust.Principals(principalType) {
+				lines = append(lines, fmt.Sprintf("  • %s: %s", principalType, principal))
+			}
+		}
+		if actions := trust.AssumeActions(); len(actions) > 0 {
+			lines = append(lines, fmt.Sprintf("  • Actions: %s", strings.Join(actions, ", ")))
+		}
+	}
+
+	lines = append(lines, "")
+	managed, inline, err := client.CountRolePolicies(ctx, role.Name)
+	if err != nil {
+		lines = append(lines, fmt.Sprintf("Policies:     unavailable (%v)", err))
+	} else {
+		lines = append(lines,
+			fmt.Sprintf("Managed policies: %d", managed),
+			fmt.Sprintf("Inline policies:  %d", inline))
+	}
+
+	return lines
+}
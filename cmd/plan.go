@@ -0,0 +1,486 @@
+// cmd/plan.go - Destination state planning shared by dry-run and real clones
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// Strategies for inline policies over the IAM size quota
+const (
+	OversizeFail    = "fail"
+	OversizeManaged = "managed"
+)
+
+// ConvertedPolicy is an inline policy created as a customer-managed policy instead
+type ConvertedPolicy struct {
+	Name     string
+	Source   string
+	Document string
+}
+
+// RolePlan is the fully transformed destination state of a single role
+type RolePlan struct {
+	SourceRole      string
+	DestRole        string
+	Description     string
+	TrustPolicy     string
+	ManagedPolicies []string
+	// InlinePolicies is keyed by destination policy name
+	InlinePolicies map[string]string
+	// InlineSources maps destination policy names back to source names
+	InlineSources map[string]string
+	Tags          map[string]string
+	// ConvertedPolicies replace inline policies that did not fit the size quota
+	ConvertedPolicies []ConvertedPolicy
+	// SharedPolicies names batch-wide policies that replace identical inline policies
+	SharedPolicies []string
+	// PolicyHandling records how policy documents were changed to fit IAM limits
+	PolicyHandling []string
+
+	// Warnings from trust rewriting that deserve a closer look
+	Warnings []string
+	// Leaks are source-environment references left after transformation
+	Leaks []awsclient.LeakFinding
+	// Violations are IAM quotas the destination role would exceed
+	Violations []awsclient.LimitViolation
+}
+
+// buildRolePlan applies every transformation to a source role
+func buildRolePlan(roleInfo *awsclient.RoleInfo, destRole string, config *CloneConfig) (*RolePlan, error) {
+	trustPolicy, warnings, err := transformTrustPolicy(roleInfo.TrustPolicy, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform trust policy: %v", err)
+	}
+
+	// Per-role overrides from a mapping file
+	row := config.Mapping.Row(roleInfo.RoleName)
+
+	plan := &RolePlan{
+		SourceRole:     roleInfo.RoleName,
+		DestRole:       destRole,
+		TrustPolicy:    row.Replace(trustPolicy),
+		InlinePolicies: make(map[string]string),
+		InlineSources:  make(map[string]string),
+		Tags:           make(map[string]string),
+		Warnings:       warnings,
+	}
+
+	for _, policyArn := range roleInfo.ManagedPolicies {
+		if !row.Detaches(policyArn) {
+			plan.ManagedPolicies = append(plan.ManagedPolicies, policyArn)
+		}
+	}
+	if row != nil {
+		plan.ManagedPolicies = append(plan.ManagedPolicies, row.AttachPolicies...)
+	}
+
+	for policyName, policyDocument := range roleInfo.InlinePolicies {
+		if row.Drops(policyName) {
+			continue
+		}
+		newPolicyName := row.Replace(awsclient.GenerateNewRoleName(policyName, config.SourcePattern, config.DestPattern))
+		plan.InlinePolicies[newPolicyName] = row.Replace(awsclient.ReplacePatternInJSON(
+			policyDocument, config.SourcePattern, config.DestPattern))
+		plan.InlineSources[newPolicyName] = policyName
+	}
+	if row != nil {
+		for policyName, policyDocument := range row.InlinePolicies {
+			plan.InlinePolicies[policyName] = policyDocument
+			delete(plan.InlineSources, policyName)
+		}
+	}
+
+	// Replace patterns in tag values, then apply the tag rules
+	tags := make(map[string]string, len(roleInfo.Tags))
+	for key, value := range roleInfo.Tags {
+		tags[key] = row.Replace(awsclient.ReplacePatternInJSON(value, config.SourcePattern, config.DestPattern))
+	}
+	tagCtx := tagContext(roleInfo, destRole, config)
+	plan.Tags, err = config.TagRules.Apply(tags, tagCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply tag rules: %v", err)
+	}
+	if row != nil {
+		for key, value := range row.Tags {
+			plan.Tags[key] = value
+		}
+	}
+
+	// Provenance tags go on last so tag rules cannot drop them
+	if config.Provenance {
+		contentHash, err := awsclient.RoleContentHash(roleInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash source role: %v", err)
+		}
+		provenance := awsclient.Provenance{
+			SourceAccount: config.SourceAccount,
+			SourceRoleArn: roleInfo.Arn,
+			ContentHash:   contentHash,
+			ToolVersion:   Version,
+			RunID:         config.RunID,
+		}
+		for key, value := range provenance.Tags() {
+			plan.Tags[key] = value
+		}
+	}
+
+	plan.Description, err = buildDescription(roleInfo.Description, tagCtx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fitPolicies(plan, config); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// fitPolicies minifies documents and moves oversize inline policies to
+// customer-managed policies, recording each step in the plan
+func fitPolicies(plan *RolePlan, config *CloneConfig) error {
+	if config.MinifyPolicies {
+		trustPolicy, err := awsclient.MinifyPolicy(plan.TrustPolicy)
+		if err != nil {
+			return fmt.Errorf("failed to minify trust policy: %v", err)
+		}
+		plan.TrustPolicy = trustPolicy
+
+		for name, document := range plan.InlinePolicies {
+			minified, err := awsclient.MinifyPolicy(document)
+			if err != nil {
+				return fmt.Errorf("failed to minify inline policy %s: %v", name, err)
+			}
+			plan.InlinePolicies[name] = minified
+		}
+		plan.PolicyHandling = append(plan.PolicyHandling, "minified policy documents")
+	}
+
+	total := 0
+	for _, document := range plan.InlinePolicies {
+		total += awsclient.PolicySize(document)
+	}
+	if total <= config.Limits.InlinePolicyTotalSize {
+		return nil
+	}
+
+	if config.OversizeInline != OversizeManaged {
+		// More, smaller inline policies would still count against the same quota;
+		// shared policies move the documents off the role later
+		if !config.ShareInlinePolicies {
+			plan.PolicyHandling = append(plan.PolicyHandling,
+				fmt.Sprintf("inline policies total %d characters; the %d-character quota covers all inline policies of a role combined, so splitting them cannot help (use --oversize-inline managed)",
+					total, config.Limits.InlinePolicyTotalSize))
+		}
+		return nil
+	}
+
+	// Move the largest policies first so as few as possible leave the role
+	names := plan.InlinePolicyNames()
+	sort.SliceStable(names, func(i, j int) bool {
+		return awsclient.PolicySize(plan.InlinePolicies[names[i]]) > awsclient.PolicySize(plan.InlinePolicies[names[j]])
+	})
+
+	for _, name := range names {
+		if total <= config.Limits.InlinePolicyTotalSize {
+			break
+		}
+
+		document := plan.InlinePolicies[name]
+		parts, err := awsclient.SplitPolicy(document, config.Limits.ManagedPolicySize)
+		if err != nil {
+			return fmt.Errorf("failed to convert inline policy %s: %v", name, err)
+		}
+
+		managedName := fmt.Sprintf("%s-%s", plan.DestRole, name)
+		var partNames []string
+		for i, part := range parts {
+			partName := managedName
+			if len(parts) > 1 {
+				partName = fmt.Sprintf("%s-%d", managedName, i+1)
+			}
+			partName = fitPolicyName(partName, config.Limits.PolicyNameLength)
+			partNames = append(partNames, partName)
+
+			plan.ConvertedPolicies = append(plan.ConvertedPolicies, ConvertedPolicy{
+				Name:     partName,
+				Source:   name,
+				Document: part,
+			})
+		}
+
+		if len(parts) > 1 {
+			plan.PolicyHandling = append(plan.PolicyHandling,
+				fmt.Sprintf("inline policy %s split into %d managed policies %s", name, len(parts), strings.Join(partNames, ", ")))
+		} else {
+			plan.PolicyHandling = append(plan.PolicyHandling,
+				fmt.Sprintf("inline policy %s converted to managed policy %s", name, partNames[0]))
+		}
+
+		total -= awsclient.PolicySize(document)
+		delete(plan.InlinePolicies, name)
+		delete(plan.InlineSources, name)
+	}
+
+	return nil
+}
+
+// fitPolicyName shortens a generated policy name to the name quota, keeping a
+// hash of the full name so that truncated names stay distinct
+func fitPolicyName(name string, maxLength int) string {
+	if utf8.RuneCountInString(name) <= maxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8]
+	return string([]rune(name)[:maxLength-len(suffix)]) + suffix
+}
+
+// customerPolicyArn returns the ARN of a customer-managed policy created without a path
+func customerPolicyArn(account, name string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:policy/%s", account, name)
+}
+
+// buildDescription keeps the source description and wraps it in the optional
+// prefix and suffix templates
+func buildDescription(description string, tagCtx awsclient.TagContext, config *CloneConfig) (string, error) {
+	if config.DescriptionPrefix != nil {
+		prefix, err := awsclient.RenderTagTemplate(config.DescriptionPrefix, tagCtx)
+		if err != nil {
+			return "", err
+		}
+		description = prefix + description
+	}
+
+	if config.DescriptionSuffix != nil {
+		suffix, err := awsclient.RenderTagTemplate(config.DescriptionSuffix, tagCtx)
+		if err != nil {
+			return "", err
+		}
+		description += suffix
+	}
+
+	return description, nil
+}
+
+// tagContext returns the values available to tag templates for a role
+func tagContext(roleInfo *awsclient.RoleInfo, destRole string, config *CloneConfig) awsclient.TagContext {
+	return awsclient.TagContext{
+		SourceRole:        roleInfo.RoleName,
+		SourceRoleArn:     roleInfo.Arn,
+		DestRole:          destRole,
+		SourceAccount:     config.SourceAccount,
+		DestAccount:       config.DestAccount,
+		SourceEnvironment: awsclient.EnvironmentToken(config.SourcePattern),
+		DestEnvironment:   awsclient.EnvironmentToken(config.DestPattern),
+		CloneDate:         config.StartedAt.Format("2006-01-02"),
+	}
+}
+
+// describeTagChanges lists destination tags next to their source values
+func describeTagChanges(sourceTags, destTags map[string]string) []string {
+	keys := make([]string, 0, len(destTags))
+	for key := range destTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		oldValue, existed := sourceTags[key]
+		switch {
+		case !existed:
+			lines = append(lines, fmt.Sprintf("%s: %s (new)", key, destTags[key]))
+		case oldValue != destTags[key]:
+			lines = append(lines, fmt.Sprintf("%s: %s → %s", key, oldValue, destTags[key]))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s", key, oldValue))
+		}
+	}
+
+	var removed []string
+	for key := range sourceTags {
+		if _, ok := destTags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		lines = append(lines, fmt.Sprintf("%s (removed)", key))
+	}
+
+	return lines
+}
+
+// InlinePolicyNames returns destination inline policy names in a stable order
+func (p *RolePlan) InlinePolicyNames() []string {
+	names := make([]string, 0, len(p.InlinePolicies))
+	for name := range p.InlinePolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scanForLeaks checks every destination name, document and tag for source references
+func scanForLeaks(plan *RolePlan, config *CloneConfig) error {
+	scanner := awsclient.NewLeakScanner(config.SourcePattern, config.DestPattern,
+		config.SourceAccount, config.DestAccount)
+
+	leaks := scanner.ScanString("role name", "RoleName", plan.DestRole)
+	leaks = append(leaks, scanner.ScanString("description", "Description", plan.Description)...)
+
+	trustLeaks, err := scanner.ScanDocument("trust policy", plan.TrustPolicy)
+	if err != nil {
+		return err
+	}
+	leaks = append(leaks, trustLeaks...)
+
+	for _, policyArn := range plan.ManagedPolicies {
+		leaks = append(leaks, scanner.ScanString("managed policy", "PolicyArn", policyArn)...)
+	}
+
+	for _, policyName := range plan.InlinePolicyNames() {
+		location := fmt.Sprintf("inline policy %s", policyName)
+		leaks = append(leaks, scanner.ScanString(location, "PolicyName", policyName)...)
+
+		documentLeaks, err := scanner.ScanDocument(location, plan.InlinePolicies[policyName])
+		if err != nil {
+			return err
+		}
+		leaks = append(leaks, documentLeaks...)
+	}
+
+	for _, converted := range plan.ConvertedPolicies {
+		location := fmt.Sprintf("managed policy %s", converted.Name)
+		leaks = append(leaks, scanner.ScanString(location, "PolicyName", converted.Name)...)
+
+		documentLeaks, err := scanner.ScanDocument(location, converted.Document)
+		if err != nil {
+			return err
+		}
+		leaks = append(leaks, documentLeaks...)
+	}
+
+	for _, key := range awsclient.SortedKeys(plan.Tags) {
+		// Provenance tags point at the source on purpose
+		if strings.HasPrefix(key, awsclient.ProvenanceTagPrefix) {
+			continue
+		}
+		leaks = append(leaks, scanner.ScanString("tag", "Key", key)...)
+		leaks = append(leaks, scanner.ScanString("tag", fmt.Sprintf("Tags[%q]", key), plan.Tags[key])...)
+	}
+
+	plan.Leaks = leaks
+	return nil
+}
+
+// checkLeakage builds the plan for each role and reports leftover source references
+func checkLeakage(config *CloneConfig, log *logger.Logger) {
+	config.Plans = make(map[string]*RolePlan)
+	config.LeakingRoles = nil
+
+	for _, role := range config.Roles {
+		roleInfo, ok := config.RoleInfos[role]
+		if !ok {
+			continue
+		}
+
+		plan, err := buildRolePlan(roleInfo, destRoleName(config, role), config)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not plan %s: %v", role, err))
+			continue
+		}
+		config.Plans[role] = plan
+
+		if err := scanForLeaks(plan, config); err != nil {
+			log.Warning(fmt.Sprintf("Could not scan %s for leftover source references: %v", role, err))
+			continue
+		}
+
+		if len(plan.Leaks) == 0 {
+			continue
+		}
+
+		config.LeakingRoles = append(config.LeakingRoles, role)
+		log.Warning(fmt.Sprintf("%s → %s still references the source environment:", role, plan.DestRole))
+		for _, leak := range plan.Leaks {
+			log.Warning(fmt.Sprintf("  %s", leak))
+		}
+	}
+
+	if len(config.LeakingRoles) > 0 {
+		if config.StrictLeakage {
+			log.Warning(fmt.Sprintf("Strict mode: %d role(s) will fail because of leftover source references",
+				len(config.LeakingRoles)))
+		} else {
+			log.Warning("Review the mapping or use --strict-leakage to fail roles with leftover source references")
+		}
+	} else if len(config.Plans) > 0 {
+		log.Success("No leftover source references after transformation")
+	}
+}
+
+// validatePlan checks the destination role against the configured IAM limits
+func validatePlan(plan *RolePlan, config *CloneConfig) {
+	managedPolicies := append([]string{}, plan.ManagedPolicies...)
+	for _, converted := range plan.ConvertedPolicies {
+		managedPolicies = append(managedPolicies, converted.Name)
+	}
+	managedPolicies = append(managedPolicies, plan.SharedPolicies...)
+
+	plan.Violations = config.Limits.ValidateRole(plan.DestRole, plan.TrustPolicy,
+		managedPolicies, plan.InlinePolicies, plan.Tags)
+	plan.Violations = append(plan.Violations, config.Limits.ValidateDescription(plan.Description)...)
+	for _, converted := range plan.ConvertedPolicies {
+		plan.Violations = append(plan.Violations,
+			config.Limits.ValidateManagedPolicy(converted.Name, converted.Document)...)
+	}
+	for _, name := range plan.SharedPolicies {
+		if shared, ok := config.SharedPolicies[name]; ok {
+			plan.Violations = append(plan.Violations,
+				config.Limits.ValidateManagedPolicy(name, shared.Document)...)
+		}
+	}
+}
+
+// checkLimits reports every IAM quota violation in the batch before any write
+func checkLimits(config *CloneConfig, log *logger.Logger) {
+	config.OverLimitRoles = nil
+
+	for _, role := range config.Roles {
+		plan, ok := config.Plans[role]
+		if !ok {
+			continue
+		}
+
+		validatePlan(plan, config)
+		for _, handling := range plan.PolicyHandling {
+			log.Info(fmt.Sprintf("%s: %s", role, handling))
+		}
+		if len(plan.Violations) == 0 {
+			continue
+		}
+
+		config.OverLimitRoles = append(config.OverLimitRoles, role)
+		log.Warning(fmt.Sprintf("%s → %s exceeds IAM limits:", role, plan.DestRole))
+		for _, violation := range plan.Violations {
+			log.Warning(fmt.Sprintf("  %s", violation))
+		}
+	}
+
+	if len(config.OverLimitRoles) > 0 {
+		log.Warning(fmt.Sprintf("%d role(s) exceed IAM limits and will not be cloned", len(config.OverLimitRoles)))
+	} else if len(config.Plans) > 0 {
+		log.Success("All roles are within IAM limits")
+	}
+}
@@ -0,0 +1,59 @@
+// cmd/progress.go - Aggregate progress bar for long batches
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Width of the bar in characters
+const progressBarWidth = 30
+
+// Roles fetched at the same time unless a command says otherwise
+const defaultParallelism = 8
+
+// progressBar draws a single progress line on stderr. It stays silent when
+// stderr is not a terminal, so redirected output is not cluttered.
+type progressBar struct {
+	label   string
+	total   int
+	done    int
+	enabled bool
+}
+
+// newProgressBar starts a bar for total items
+func newProgressBar(label string, total int) *progressBar {
+	p := &progressBar{
+		label:   label,
+		total:   total,
+		enabled: total > 0 && term.IsTerminal(int(os.Stderr.Fd())),
+	}
+	p.draw()
+	return p
+}
+
+// Increment marks one more item as done
+func (p *progressBar) Increment() {
+	p.done++
+	p.draw()
+}
+
+// Finish clears the bar
+func (p *progressBar) Finish() {
+	if p.enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// Helper function to redraw the bar in place
+func (p *progressBar) draw() {
+	if !p.enabled {
+		return
+	}
+	filled := p.done * progressBarWidth / p.total
+	fmt.Fprintf(os.Stderr, "\r%s [%s%s] %d/%d", p.label,
+		strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled), p.done, p.total)
+}
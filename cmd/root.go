@@ -37,6 +37,13 @@ Example usage:
 		fmt.Println("Available commands:")
 		fmt.Println("  clone    Clone IAM roles between profiles")
 		fmt.Println("  list     List IAM roles in a profile")
+		fmt.Println("  audit    Audit IAM roles for risky trust configuration")
+		fmt.Println("  lineage  Show which roles were cloned from which source")
+		fmt.Println("  drift    Detect drift between source roles and their clones")
+		fmt.Println("  delete   Delete roles, e.g. a bad clone batch")
+		fmt.Println("  undo     Undo a previous clone run from its journal")
+		fmt.Println("  graph    Export the trust and permission graph of roles")
+		fmt.Println("  search   Find roles by action, resource or principal")
 		fmt.Println("  version  Show version information")
 		fmt.Println()
 		fmt.Println("Use 'iam-role-cloner [command] --help' for more information about a command.")
@@ -0,0 +1,244 @@
+// cmd/search.go - Find roles by action, resource or principal
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// searchCmd finds the roles whose policies grant an action or trust a principal
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Find roles by action, resource or principal",
+	Long: `Scan the inline policies, managed policy documents and trust policies of the
+roles in a profile and show the roles with the statements that matched.
+
+--action and --resource are matched together against Allow statements of
+permission policies; --principal against Allow statements of trust policies.
+Actions use IAM wildcard semantics (s3:Delete* grants s3:DeleteObject), and
+resources and principals are globs. A principal that is an account ID matches
+every principal of that account, and trust in "*" matches any principal. With
+several kinds of criteria, a role must match all of them.
+
+Examples:
+  iam-role-cloner search -p prod --action s3:DeleteObject --resource 'arn:aws:s3:::billing-exports/*'
+  iam-role-cloner search -p prod --principal 222233334444
+  iam-role-cloner search -p prod --principal '*.amazonaws.com' --select 'path:/service-role/'
+  iam-role-cloner search -p prod --action 'iam:Pass*' --output json`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, _ := cmd.Flags().GetString("profile")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		actions, _ := cmd.Flags().GetStringSlice("action")
+		resources, _ := cmd.Flags().GetStringSlice("resource")
+		principals, _ := cmd.Flags().GetStringSlice("principal")
+		output, _ := cmd.Flags().GetString("output")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if output != "table" && output != "json" {
+			fmt.Printf("❌ Error: unsupported output format '%s' (use 'table' or 'json')\n", output)
+			os.Exit(1)
+		}
+
+		query := awsclient.SearchQuery{Actions: actions, Resources: resources, Principals: principals}
+		if !query.HasPermissionCriteria() && len(query.Principals) == 0 {
+			fmt.Println("❌ Error: at least one of --action, --resource or --principal is required")
+			os.Exit(1)
+		}
+
+		selector, err := buildRoleSelector(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := runSearch(profile, pattern, selector, query, output, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runSearch(profile, pattern string, selector *awsclient.RoleSelector, query awsclient.SearchQuery,
+	output string, verbose bool) error {
+
+	table := output == "table"
+
+	log, err := logger.New(verbose, "")
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+	if !table {
+		log.SetOutput(os.Stderr)
+	}
+
+	if table {
+		log.Header(fmt.Sprintf("🔎 Role Search in Profile: %s", profile))
+		for _, line := range describeSearchQuery(query) {
+			log.Info(line)
+		}
+	}
+
+	client, err := awsclient.NewClient(profile)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.ValidateCredentials(ctx); err != nil {
+		return fmt.Errorf("failed to validate credentials: %v", err)
+	}
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s.Suffix = " Searching roles..."
+	if table {
+		s.Start()
+	}
+	matches, roleCount, err := searchRoles(ctx, client, pattern, selector, query, log)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if !table {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(matches)
+	}
+
+	log.Separator()
+	if len(matches) == 0 {
+		log.Info(fmt.Sprintf("No matches in %d roles", roleCount))
+		return nil
+	}
+
+	printSearchMatches(matches)
+
+	roles := make(map[string]bool)
+	for _, match := range matches {
+		roles[match.Role] = true
+	}
+	log.Separator()
+	log.Success(fmt.Sprintf("%d matching statement(s) in %d of %d roles", len(matches), len(roles), roleCount))
+
+	return nil
+}
+
+// searchRoles runs the query over the selected roles, fetching permission
+// policies only when the query needs them
+func searchRoles(ctx context.Context, client *awsclient.Client, pattern string, selector *awsclient.RoleSelector,
+	query awsclient.SearchQuery, log *logger.Logger) ([]awsclient.SearchMatch, int, error) {
+
+	roles, err := client.ListRoleSummaries(ctx, "")
+	if err != nil {
+		return nil, 0, err
+	}
+	if pattern != "" {
+		selector.RequireNameContaining(pattern)
+	}
+	roles = filterRoleSummaries(ctx, client, roles, selector, log)
+
+	matches := []awsclient.SearchMatch{}
+	managedDocs := make(map[string]string)
+
+	for _, role := range roles {
+		var inline, managed map[string]string
+
+		if query.HasPermissionCriteria() {
+			roleInfo, err := client.GetRoleInfo(ctx, role.Name)
+			if err != nil {
+				log.Warning(fmt.Sprintf("Skipping %s: %v", role.Name, err))
+				continue
+			}
+
+			inline = roleInfo.InlinePolicies
+			managed = make(map[string]string)
+			documents := rolePolicyDocuments(ctx, client, managedDocs, roleInfo, log)
+			for _, policyArn := range roleInfo.ManagedPolicies {
+				if document, ok := documents[policyArn]; ok {
+					managed[policyArn] = document
+				}
+			}
+		}
+
+		found, err := awsclient.SearchRole(role.Name, role.TrustPolicy, inline, managed, query)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Skipping %s: %v", role.Name, err))
+			continue
+		}
+		matches = append(matches, found...)
+	}
+
+	return matches, len(roles), nil
+}
+
+// Helper function to restate the query for the table header
+func describeSearchQuery(query awsclient.SearchQuery) []string {
+	var lines []string
+	if len(query.Actions) > 0 {
+		lines = append(lines, fmt.Sprintf("Actions:    %s", strings.Join(query.Actions, ", ")))
+	}
+	if len(query.Resources) > 0 {
+		lines = append(lines, fmt.Sprintf("Resources:  %s", strings.Join(query.Resources, ", ")))
+	}
+	if len(query.Principals) > 0 {
+		lines = append(lines, fmt.Sprintf("Principals: %s", strings.Join(query.Principals, ", ")))
+	}
+	return lines
+}
+
+func printSearchMatches(matches []awsclient.SearchMatch) {
+	previous := ""
+	for _, match := range matches {
+		if match.Role != previous {
+			fmt.Printf("\n🔑 %s\n", match.Role)
+			previous = match.Role
+		}
+
+		location := match.Source
+		if match.Policy != "" {
+			location += " " + match.Policy
+		}
+		if match.Sid != "" {
+			location += fmt.Sprintf(" (Sid %s)", match.Sid)
+		}
+		fmt.Printf("   📄 %s — matched %s\n", location, strings.Join(match.Matched, ", "))
+
+		var statement bytes.Buffer
+		if err := json.Indent(&statement, match.Statement, "      ", "  "); err != nil {
+			statement.Write(match.Statement)
+		}
+		fmt.Printf("      %s\n", statement.String())
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	// Required flags
+	searchCmd.Flags().StringP("profile", "p", "", "AWS profile to use (required)")
+	searchCmd.MarkFlagRequired("profile")
+
+	// Search criteria
+	searchCmd.Flags().StringSlice("action", nil, "Actions the role may perform, with IAM wildcards (comma-separated or repeated)")
+	searchCmd.Flags().StringSlice("resource", nil, "Resource ARNs or globs the actions apply to")
+	searchCmd.Flags().StringSlice("principal", nil, "Account IDs, principal ARNs, services or globs the role trusts")
+
+	// Optional flags
+	searchCmd.Flags().String("pattern", "", "Only search roles whose name contains this pattern (case-insensitive)")
+	searchCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+	searchCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	addSelectorFlags(searchCmd)
+}
@@ -0,0 +1,182 @@
+// cmd/selector.go - Role selector flags shared by list and clone
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/logger"
+)
+
+// indexSelection matches interactive selections such as "1,3,5-7"
+var indexSelection = regexp.MustCompile(`^\s*\d+(\s*-\s*\d+)?(\s*,\s*\d+(\s*-\s*\d+)?)*\s*,?\s*$`)
+
+// addSelectorFlags registers the role selector flags on a command
+func addSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().String("select", "", "Role selector expression (e.g., 'dev_* and tag:Team=payments and not lastused>90d')")
+	cmd.Flags().StringArray("include", nil, "Only keep roles matching any of these selectors; '@file' reads one per line")
+	cmd.Flags().StringArray("exclude", nil, "Drop roles matching any of these selectors; '@file' reads one per line")
+}
+
+// buildRoleSelector turns the selector flags into a RoleSelector
+func buildRoleSelector(cmd *cobra.Command) (*awsclient.RoleSelector, error) {
+	expression, _ := cmd.Flags().GetString("select")
+	include, _ := cmd.Flags().GetStringArray("include")
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+
+	include, err := expandSelectorList(include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err = expandSelectorList(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return awsclient.NewRoleSelector(expression, include, exclude)
+}
+
+// Helper function to expand '@file' entries into one selector per non-comment line
+func expandSelectorList(entries []string) ([]string, error) {
+	var expanded []string
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry, "@") {
+			expanded = append(expanded, entry)
+			continue
+		}
+
+		data, err := os.ReadFile(entry[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read selector list: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				expanded = append(expanded, line)
+			}
+		}
+	}
+
+	return expanded, nil
+}
+
+// filterRoleSummaries applies a selector, fetching tags and last-used dates first
+// when the selector needs them. Roles whose details cannot be read are skipped.
+func filterRoleSummaries(ctx context.Context, client *awsclient.Client, roles []*awsclient.RoleSummary,
+	selector *awsclient.RoleSelector, log *logger.Logger) []*awsclient.RoleSummary {
+
+	if selector.IsEmpty() {
+		return roles
+	}
+
+	candidates := roles
+	if selector.NeedsDetails() {
+		candidates = loadRoleDetails(ctx, client, roles, defaultParallelism, "skipping", log)
+	}
+
+	return selector.Filter(candidates)
+}
+
+// loadRoleDetails fetches the tags and last-used dates of the roles that lack
+// them, parallel roles at a time, and returns the roles that could be read.
+// outcome tells the user what happens to a role that could not be read.
+func loadRoleDetails(ctx context.Context, client *awsclient.Client, roles []*awsclient.RoleSummary,
+	parallel int, outcome string, log *logger.Logger) []*awsclient.RoleSummary {
+
+	var pending []*awsclient.RoleSummary
+	for _, role := range roles {
+		if !role.DetailsLoaded {
+			pending = append(pending, role)
+		}
+	}
+
+	bar := newProgressBar("Getting tags and last-used dates", len(pending))
+	errs := client.LoadRoleDetailsAll(ctx, pending, parallel, bar.Increment)
+	bar.Finish()
+
+	for i, err := range errs {
+		if err != nil {
+			log.Warning(fmt.Sprintf("Could not read %s, %s: %v", pending[i].Name, outcome, err))
+		}
+	}
+
+	loaded := make([]*awsclient.RoleSummary, 0, len(roles))
+	for _, role := range roles {
+		if role.DetailsLoaded {
+			loaded = append(loaded, role)
+		}
+	}
+	return loaded
+}
+
+// parseRoleSelection resolves an interactive selection: indexes and ranges such as
+// "1,3,5-7", or otherwise a selector expression applied to the listed roles
+func parseRoleSelection(ctx context.Context, client *awsclient.Client, selection string,
+	allRoles []*awsclient.RoleSummary, log *logger.Logger) ([]string, error) {
+
+	if !indexSelection.MatchString(selection) {
+		selector, err := awsclient.NewRoleSelector(selection, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var selectedRoles []string
+		for _, role := range filterRoleSummaries(ctx, client, allRoles, selector, log) {
+			selectedRoles = append(selectedRoles, role.Name)
+		}
+		if len(selectedRoles) == 0 {
+			return nil, fmt.Errorf("no listed role matches '%s'", selection)
+		}
+		return selectedRoles, nil
+	}
+
+	var selectedRoles []string
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(selection, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last := part, part
+		if from, to, isRange := strings.Cut(part, "-"); isRange {
+			first, last = strings.TrimSpace(from), strings.TrimSpace(to)
+		}
+
+		start, _ := strconv.Atoi(first)
+		end, _ := strconv.Atoi(last)
+		if start > end {
+			return nil, fmt.Errorf("invalid range: %s", part)
+		}
+		if start < 1 || end > len(allRoles) {
+			return nil, fmt.Errorf("number out of range: %s", part)
+		}
+
+		for index := start; index <= end; index++ {
+			if !seen[index] {
+				seen[index] = true
+				selectedRoles = append(selectedRoles, allRoles[index-1].Name)
+			}
+		}
+	}
+
+	return selectedRoles, nil
+}
+
+// Helper function to list role names
+func roleSummaryNames(roles []*awsclient.RoleSummary) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
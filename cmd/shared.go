@@ -0,0 +1,128 @@
+// cmd/shared.go - Shared customer-managed policies for identical inline policies
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/journal"
+	"iam-role-cloner/internal/logger"
+)
+
+// SharedPolicy is one customer-managed policy created for every role in the
+// batch that carried the same inline document
+type SharedPolicy struct {
+	Name     string
+	Hash     string
+	Document string
+	// Roles lists the source roles that attach the policy
+	Roles []string
+	// Arn is set once the policy exists in the destination
+	Arn string
+}
+
+// planSharedPolicies groups identical inline policies across the batch and moves
+// them from each role plan to a shared customer-managed policy
+func planSharedPolicies(config *CloneConfig, log *logger.Logger) {
+	config.SharedPolicies = make(map[string]*SharedPolicy)
+	byHash := make(map[string]*SharedPolicy)
+	inlineCount := 0
+
+	for _, role := range config.Roles {
+		plan, ok := config.Plans[role]
+		if !ok {
+			continue
+		}
+
+		for _, name := range plan.InlinePolicyNames() {
+			document := plan.InlinePolicies[name]
+			hash, err := awsclient.PolicyHash(document)
+			if err != nil {
+				log.Warning(fmt.Sprintf("Could not hash inline policy %s of %s, keeping it inline: %v", name, role, err))
+				continue
+			}
+
+			shared, ok := byHash[hash]
+			if !ok {
+				sharedName := name
+				if _, taken := config.SharedPolicies[sharedName]; taken {
+					// Same name, different document: keep both apart
+					sharedName = fmt.Sprintf("%s-%s", name, hash[:8])
+				}
+
+				shared = &SharedPolicy{Name: sharedName, Hash: hash, Document: document}
+				byHash[hash] = shared
+				config.SharedPolicies[sharedName] = shared
+			}
+
+			shared.Roles = append(shared.Roles, role)
+			plan.SharedPolicies = append(plan.SharedPolicies, shared.Name)
+			plan.PolicyHandling = append(plan.PolicyHandling,
+				fmt.Sprintf("inline policy %s attached as shared managed policy %s", name, shared.Name))
+
+			delete(plan.InlinePolicies, name)
+			delete(plan.InlineSources, name)
+			inlineCount++
+		}
+	}
+
+	if inlineCount == 0 {
+		return
+	}
+
+	log.Success(fmt.Sprintf("%d inline policies map to %d shared managed policies",
+		inlineCount, len(config.SharedPolicies)))
+	for _, name := range sharedPolicyNames(config) {
+		shared := config.SharedPolicies[name]
+		log.Debug(fmt.Sprintf("  %s (%s): %s", name, shared.Hash[:12], strings.Join(shared.Roles, ", ")))
+	}
+}
+
+// createSharedPolicies creates each shared policy once before any role is cloned
+func createSharedPolicies(ctx context.Context, destClient *awsclient.Client, config *CloneConfig, log *logger.Logger) {
+	for _, name := range sharedPolicyNames(config) {
+		shared := config.SharedPolicies[name]
+
+		if config.DryRun {
+			log.Info(fmt.Sprintf("  [DRY RUN] Would create shared policy %s for %d role(s)", name, len(shared.Roles)))
+			continue
+		}
+
+		description := fmt.Sprintf("Shared inline policy of %d cloned role(s)", len(shared.Roles))
+		arn, err := destClient.CreateManagedPolicy(ctx, name, shared.Document, description)
+		switch {
+		case err == nil:
+			shared.Arn = arn
+			recordMutation(config, log, journal.Entry{
+				Action: journal.ActionCreatePolicy, PolicyArn: arn, PolicyName: name, Hash: shared.Hash,
+			})
+			log.Success(fmt.Sprintf("  Created shared policy: %s", arn))
+		case awsclient.IsEntityAlreadyExists(err):
+			// An earlier run created the policy; bring its document up to date
+			arn = customerPolicyArn(config.DestAccount, name)
+			updated, err := destClient.UpdateManagedPolicy(ctx, arn, shared.Document)
+			if err != nil {
+				log.Warning(fmt.Sprintf("  Failed to update shared policy %s, roles will keep it inline: %v", name, err))
+				continue
+			}
+			shared.Arn = arn
+			if updated {
+				recordMutation(config, log, journal.Entry{
+					Action: journal.ActionUpdatePolicy, PolicyArn: arn, PolicyName: name, Hash: shared.Hash,
+				})
+				log.Success(fmt.Sprintf("  Updated existing shared policy: %s", arn))
+			} else {
+				log.Success(fmt.Sprintf("  Shared policy exists: %s", arn))
+			}
+		default:
+			log.Warning(fmt.Sprintf("  Failed to create shared policy %s, roles will keep it inline: %v", name, err))
+		}
+	}
+}
+
+// Helper function to list shared policy names in a stable order
+func sharedPolicyNames(config *CloneConfig) []string {
+	return awsclient.SortedKeys(config.SharedPolicies)
+}
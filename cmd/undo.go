@@ -0,0 +1,440 @@
+// cmd/undo.go - Reverse the changes of a previous clone run from its journal
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	awsclient "iam-role-cloner/internal/aws"
+	"iam-role-cloner/internal/journal"
+	"iam-role-cloner/internal/logger"
+)
+
+// undoStep is one reversal; Refused explains why it will not be performed
+type undoStep struct {
+	Description string
+	Refused     string
+	Apply       func(ctx context.Context) error
+}
+
+// journalRole is the state a run left a role in
+type journalRole struct {
+	Created   bool
+	TrustHash string
+	Managed   map[string]bool
+	Inline    map[string]string
+	Tagged    bool
+	Synced    bool
+}
+
+// undoCmd reverses exactly the mutations recorded in a clone journal
+var undoCmd = &cobra.Command{
+	Use:   "undo <run-id|journal-file>",
+	Short: "Undo a previous clone run",
+	Long: `Reverse the changes a clone run made, using the journal it wrote
+(iam-clone-<run-id>.journal.jsonl).
+
+Before touching anything, each resource is checked against the journal:
+- roles must still have the trust policy, managed policies and inline policies
+  the run gave them, and no instance profiles
+- policies the run created must still have a single, unchanged version and no
+  attachments once the run's roles are gone
+- identity providers the run created must not be trusted by any other role
+Existing roles and policies the run updated in place (clone --on-collision sync)
+cannot be restored and are reported. Anything that changed since the run is reported and left alone.
+
+Examples:
+  iam-role-cloner undo 20250101-120000-9f86d081884c7d65 --dry-run
+  iam-role-cloner undo iam-clone-20250101-120000-9f86d081884c7d65.journal.jsonl
+  iam-role-cloner undo 20250101-120000-9f86d081884c7d65 --yes`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, _ := cmd.Flags().GetString("profile")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if err := runUndo(args[0], profile, dryRun, yes, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runUndo(run, profile string, dryRun, yes, verbose bool) error {
+	journalFile := run
+	if _, err := os.Stat(journalFile); err != nil {
+		journalFile = fmt.Sprintf("iam-clone-%s.journal.jsonl", run)
+	}
+
+	runEntry, entries, err := journal.Read(journalFile)
+	if err != nil {
+		return err
+	}
+	if profile == "" {
+		profile = runEntry.Profile
+	}
+
+	logFile := fmt.Sprintf("iam-undo-%s-%s.log", runEntry.RunID, time.Now().Format("20060102-150405"))
+	log, err := logger.New(verbose, logFile)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	defer log.Close()
+
+	log.Header(fmt.Sprintf("⏪ Undo Clone Run: %s", runEntry.RunID))
+	log.Info(fmt.Sprintf("Journal: %s (%d changes)", journalFile, len(entries)))
+
+	client, err := awsclient.NewClient(profile)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	ctx := context.Background()
+	identity, err := client.ValidateCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials: %v", err)
+	}
+	if *identity.Account != runEntry.Account {
+		return fmt.Errorf("profile %s is account %s, but run %s changed account %s",
+			profile, *identity.Account, runEntry.RunID, runEntry.Account)
+	}
+	log.Success(fmt.Sprintf("Connected to AWS Account: %s", *identity.Account))
+
+	steps, err := planUndo(ctx, client, entries, log)
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		log.Info("Nothing left to undo")
+		return nil
+	}
+
+	log.Separator()
+	log.Info("Undo plan:")
+	refused := 0
+	for i, step := range steps {
+		if step.Refused != "" {
+			refused++
+			fmt.Printf("  %d. SKIP %s: %s\n", i+1, step.Description, step.Refused)
+		} else {
+			fmt.Printf("  %d. %s\n", i+1, step.Description)
+		}
+	}
+
+	if dryRun {
+		log.Info("This was a dry run. Use without --dry-run to undo the run.")
+		return nil
+	}
+
+	if refused == len(steps) {
+		log.Warning("Every change was modified since the run; nothing will be undone")
+		return nil
+	}
+
+	if !yes {
+		fmt.Print("\nProceed with undo? (y/n): ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			log.Info("Undo cancelled")
+			return nil
+		}
+	}
+
+	log.Separator()
+	failed := 0
+	for _, step := range steps {
+		if step.Refused != "" {
+			log.Warning(fmt.Sprintf("Left alone: %s (%s)", step.Description, step.Refused))
+			continue
+		}
+		if err := step.Apply(ctx); err != nil {
+			failed++
+			log.Error(fmt.Sprintf("Failed: %s: %v", step.Description, err))
+			continue
+		}
+		log.Success(fmt.Sprintf("Done: %s", step.Description))
+	}
+
+	log.Separator()
+	if failed > 0 {
+		return fmt.Errorf("%d undo step(s) failed, see %s", failed, logFile)
+	}
+	if refused > 0 {
+		log.Warning(fmt.Sprintf("%d change(s) were modified since the run and were left alone", refused))
+	} else {
+		log.Success(fmt.Sprintf("Run %s undone", runEntry.RunID))
+	}
+
+	return nil
+}
+
+// planUndo replays the journal and checks each resource before reversing it
+func planUndo(ctx context.Context, client *awsclient.Client, entries []journal.Entry, log *logger.Logger) ([]undoStep, error) {
+	roles := make(map[string]*journalRole)
+	var roleOrder []string
+	var policies, updatedPolicies, providers []journal.Entry
+
+	roleFor := func(name string) *journalRole {
+		role, ok := roles[name]
+		if !ok {
+			role = &journalRole{Managed: make(map[string]bool), Inline: make(map[string]string)}
+			roles[name] = role
+			roleOrder = append(roleOrder, name)
+		}
+		return role
+	}
+
+	for _, entry := range entries {
+		switch entry.Action {
+		case journal.ActionCreateRole:
+			role := roleFor(entry.Role)
+			role.Created = true
+			role.TrustHash = entry.Hash
+		case journal.ActionAttachPolicy:
+			roleFor(entry.Role).Managed[entry.PolicyArn] = true
+		case journal.ActionPutRolePolicy:
+			roleFor(entry.Role).Inline[entry.PolicyName] = entry.Hash
+		case journal.ActionTagRole:
+			roleFor(entry.Role).Tagged = true
+		case journal.ActionSyncRole:
+			roleFor(entry.Role).Synced = true
+		case journal.ActionCreatePolicy:
+			policies = append(policies, entry)
+		case journal.ActionUpdatePolicy:
+			updatedPolicies = append(updatedPolicies, entry)
+		case journal.ActionCreateProvider:
+			providers = append(providers, entry)
+		}
+	}
+
+	var steps []undoStep
+	deleting := make(map[string]bool)
+
+	// Roles first, newest first
+	for i := len(roleOrder) - 1; i >= 0; i-- {
+		name := roleOrder[i]
+		step, err := planRoleUndo(ctx, client, name, roles[name])
+		if err != nil {
+			return nil, err
+		}
+		if step == nil {
+			log.Debug(fmt.Sprintf("%s no longer exists", name))
+			continue
+		}
+		if step.Refused == "" && roles[name].Created {
+			deleting[name] = true
+		}
+		steps = append(steps, *step)
+	}
+
+	for i := len(policies) - 1; i >= 0; i-- {
+		step, err := planPolicyUndo(ctx, client, policies[i])
+		if err != nil {
+			return nil, err
+		}
+		if step == nil {
+			log.Debug(fmt.Sprintf("%s no longer exists", policies[i].PolicyArn))
+			continue
+		}
+		steps = append(steps, *step)
+	}
+
+	for i := len(updatedPolicies) - 1; i >= 0; i-- {
+		steps = append(steps, undoStep{
+			Description: fmt.Sprintf("restore policy %s", updatedPolicies[i].PolicyArn),
+			Refused:     "given a new default version by the run; the previous version is still listed in IAM",
+		})
+	}
+
+	if len(providers) > 0 {
+		trustPolicies, err := client.ListRoleTrustPolicies(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		for i := len(providers) - 1; i >= 0; i-- {
+			step, err := planProviderUndo(ctx, client, providers[i].ProviderArn, trustPolicies, deleting)
+			if err != nil {
+				return nil, err
+			}
+			if step != nil {
+				steps = append(steps, *step)
+			}
+		}
+	}
+
+	return steps, nil
+}
+
+// Helper function to plan the reversal of one role; nil means nothing to undo
+func planRoleUndo(ctx context.Context, client *awsclient.Client, name string, expected *journalRole) (*undoStep, error) {
+	info, err := client.GetRoleInfo(ctx, name)
+	if awsclient.IsNoSuchEntity(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if expected.Synced {
+		return &undoStep{
+			Description: fmt.Sprintf("restore existing role %s", name),
+			Refused:     "updated in place by the run; its previous state was not recorded",
+		}, nil
+	}
+
+	deletion, err := client.PlanRoleDeletion(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []string
+	if expected.Created && documentHash(info.TrustPolicy) != expected.TrustHash {
+		reasons = append(reasons, "trust policy changed")
+	}
+
+	for _, arn := range info.ManagedPolicies {
+		if expected.Created && !expected.Managed[arn] {
+			reasons = append(reasons, fmt.Sprintf("%s attached since", arn))
+		}
+	}
+	for _, policyName := range awsclient.SortedKeys(info.InlinePolicies) {
+		hash, ok := expected.Inline[policyName]
+		switch {
+		case !ok && expected.Created:
+			reasons = append(reasons, fmt.Sprintf("inline policy %s added since", policyName))
+		case ok && documentHash(info.InlinePolicies[policyName]) != hash:
+			reasons = append(reasons, fmt.Sprintf("inline policy %s changed", policyName))
+		}
+	}
+	if len(deletion.InstanceProfiles) > 0 && expected.Created {
+		reasons = append(reasons, fmt.Sprintf("in instance profile(s) %s", strings.Join(deletion.InstanceProfiles, ", ")))
+	}
+
+	if expected.Created {
+		return &undoStep{
+			Description: fmt.Sprintf("delete role %s", name),
+			Refused:     strings.Join(reasons, "; "),
+			Apply: func(ctx context.Context) error {
+				return client.DeleteRole(ctx, deletion, false)
+			},
+		}, nil
+	}
+
+	// The run only changed an existing role: reverse just its attachments
+	step := &undoStep{
+		Description: fmt.Sprintf("remove the run's policies from existing role %s", name),
+		Refused:     strings.Join(reasons, "; "),
+		Apply: func(ctx context.Context) error {
+			for arn := range expected.Managed {
+				if err := client.DetachManagedPolicy(ctx, name, arn); err != nil {
+					return err
+				}
+			}
+			for policyName := range expected.Inline {
+				if err := client.DeleteInlinePolicy(ctx, name, policyName); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	if expected.Tagged && step.Refused == "" {
+		step.Description += " (tags are not restored)"
+	}
+	return step, nil
+}
+
+// Helper function to plan the deletion of a policy the run created; nil if it is gone
+func planPolicyUndo(ctx context.Context, client *awsclient.Client, entry journal.Entry) (*undoStep, error) {
+	state, err := client.DescribeManagedPolicy(ctx, entry.PolicyArn)
+	if awsclient.IsNoSuchEntity(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	step := &undoStep{
+		Description: fmt.Sprintf("delete policy %s", entry.PolicyArn),
+		Apply: func(ctx context.Context) error {
+			// Attachments are checked now, after the run's roles are gone
+			current, err := client.DescribeManagedPolicy(ctx, entry.PolicyArn)
+			if err != nil {
+				return err
+			}
+			if current.AttachmentCount > 0 {
+				return fmt.Errorf("still attached to %d entities, left alone", current.AttachmentCount)
+			}
+			return client.DeleteManagedPolicy(ctx, entry.PolicyArn)
+		},
+	}
+
+	switch {
+	case state.VersionCount > 1:
+		step.Refused = fmt.Sprintf("%d versions exist", state.VersionCount)
+	case documentHash(state.Document) != entry.Hash:
+		step.Refused = "policy document changed"
+	}
+	return step, nil
+}
+
+// Helper function to plan the deletion of a provider the run created; nil if it is gone
+func planProviderUndo(ctx context.Context, client *awsclient.Client, arn string,
+	trustPolicies map[string]string, deleting map[string]bool) (*undoStep, error) {
+
+	exists, err := client.IdentityProviderExists(ctx, arn)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var users []string
+	for role, trust := range trustPolicies {
+		if deleting[role] {
+			continue
+		}
+		federated, err := awsclient.FindFederatedProviders(trust)
+		if err != nil {
+			continue
+		}
+		for _, providerArn := range federated {
+			if providerArn == arn {
+				users = append(users, role)
+			}
+		}
+	}
+	sort.Strings(users)
+
+	step := &undoStep{
+		Description: fmt.Sprintf("delete identity provider %s", arn),
+		Apply: func(ctx context.Context) error {
+			return client.DeleteIdentityProvider(ctx, arn)
+		},
+	}
+	if len(users) > 0 {
+		step.Refused = fmt.Sprintf("trusted by %s", strings.Join(users, ", "))
+	}
+	return step, nil
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().StringP("profile", "p", "", "AWS profile to undo in (default: the run's destination profile)")
+	undoCmd.Flags().Bool("dry-run", false, "Show the undo plan without changing anything")
+	undoCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	undoCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+}
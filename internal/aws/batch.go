@@ -0,0 +1,58 @@
+// internal/aws/batch.go - Fetching the details of many roles concurrently
+package aws
+
+import (
+	"context"
+	"sync"
+)
+
+// RoleInfoResult is the outcome of fetching one role in GetRoleInfos
+type RoleInfoResult struct {
+	Info *RoleInfo
+	Err  error
+}
+
+// GetRoleInfos fetches the details of many roles with at most parallelism roles
+// in flight. Results are in the order of roleNames, and a failed role does not
+// stop the others. onDone, if set, is called once per finished role, never
+// concurrently.
+func (c *Client) GetRoleInfos(ctx context.Context, roleNames []string, parallelism int, onDone func()) []RoleInfoResult {
+	results := make([]RoleInfoResult, len(roleNames))
+	forEachConcurrently(len(roleNames), parallelism, func(i int) {
+		info, err := c.GetRoleInfo(ctx, roleNames[i])
+		results[i] = RoleInfoResult{Info: info, Err: err}
+	}, onDone)
+	return results
+}
+
+// Helper function to run work for indexes 0..n-1 with at most parallelism at a
+// time, calling onDone after each, never concurrently
+func forEachConcurrently(n, parallelism int, work func(i int), onDone func()) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			work(i)
+
+			if onDone != nil {
+				mu.Lock()
+				onDone()
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
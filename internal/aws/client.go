@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -22,12 +23,21 @@ type Client struct {
 }
 
 type RoleInfo struct {
-	RoleName        string
-	Description     string
-	TrustPolicy     string
-	ManagedPolicies []string
-	InlinePolicies  map[string]string
-	Tags            map[string]string
+	RoleName        string            `json:"role_name"`
+	Arn             string            `json:"arn"`
+	Description     string            `json:"description"`
+	TrustPolicy     string            `json:"trust_policy"`
+	ManagedPolicies []string          `json:"managed_policies"`
+	InlinePolicies  map[string]string `json:"inline_policies"`
+	Tags            map[string]string `json:"tags"`
+
+	Path       string    `json:"path"`
+	CreateDate time.Time `json:"create_date"`
+	// MaxSessionDuration is in seconds
+	MaxSessionDuration int32 `json:"max_session_duration"`
+	// LastUsed is nil when the role has never been used
+	LastUsed       *time.Time `json:"last_used"`
+	LastUsedRegion string     `json:"last_used_region,omitempty"`
 }
 
 // NewClient creates a new AWS client with the specified profile
@@ -74,6 +84,35 @@ func (c *Client) ListRoles(ctx context.Context, prefix string) ([]string, error)
 	return allRoles, nil
 }
 
+// ListRoleTrustPolicies returns the trust policy of every role, optionally filtered by prefix
+func (c *Client) ListRoleTrustPolicies(ctx context.Context, prefix string) (map[string]string, error) {
+	policies := make(map[string]string)
+
+	paginator := iam.NewListRolesPaginator(c.iam, &iam.ListRolesInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list roles: %v", err)
+		}
+
+		for _, role := range output.Roles {
+			roleName := *role.RoleName
+			if prefix != "" && !strings.HasPrefix(roleName, prefix) {
+				continue
+			}
+
+			trustPolicy, err := processPolicyDocument(role.AssumeRolePolicyDocument)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process trust policy of %s: %v", roleName, err)
+			}
+			policies[roleName] = trustPolicy
+		}
+	}
+
+	return policies, nil
+}
+
 // RoleExists checks if a role exists
 func (c *Client) RoleExists(ctx context.Context, roleName string) bool {
 	_, err := c.iam.GetRole(ctx, &iam.GetRoleInput{
@@ -89,15 +128,21 @@ func (c *Client) GetRoleInfo(ctx context.Context, roleName string) (*RoleInfo, e
 		RoleName: aws.String(roleName),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get role %s: %v", roleName, err)
+		return nil, fmt.Errorf("failed to get role %s: %w", roleName, err)
 	}
 
 	role := roleOutput.Role
 	roleInfo := &RoleInfo{
 		RoleName:    *role.RoleName,
+		Arn:         aws.ToString(role.Arn),
 		Description: "",
 		Tags:        make(map[string]string),
+
+		Path:               aws.ToString(role.Path),
+		CreateDate:         aws.ToTime(role.CreateDate),
+		MaxSessionDuration: aws.ToInt32(role.MaxSessionDuration),
 	}
+	roleInfo.LastUsed, roleInfo.LastUsedRegion = lastUsedOf(role.RoleLastUsed)
 
 	if role.Description != nil {
 		roleInfo.Description = *role.Description
@@ -113,27 +158,36 @@ func (c *Client) GetRoleInfo(ctx context.Context, roleName string) (*RoleInfo, e
 	// Get managed policies
 	managedPolicies, err := c.getManagedPolicies(ctx, roleName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get managed policies: %v", err)
+		return nil, fmt.Errorf("failed to get managed policies: %w", err)
 	}
 	roleInfo.ManagedPolicies = managedPolicies
 
 	// Get inline policies
 	inlinePolicies, err := c.getInlinePolicies(ctx, roleName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inline policies: %v", err)
+		return nil, fmt.Errorf("failed to get inline policies: %w", err)
 	}
 	roleInfo.InlinePolicies = inlinePolicies
 
 	// Get tags
-	tags, err := c.getRoleTags(ctx, roleName)
+	tags, err := c.GetRoleTags(ctx, roleName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tags: %v", err)
+		return nil, fmt.Errorf("failed to get tags: %w", err)
 	}
 	roleInfo.Tags = tags
 
 	return roleInfo, nil
 }
 
+// Helper function to read the last use of a role; the date is nil when it was never used
+func lastUsedOf(lastUsed *types.RoleLastUsed) (*time.Time, string) {
+	if lastUsed == nil || lastUsed.LastUsedDate == nil {
+		return nil, ""
+	}
+	date := *lastUsed.LastUsedDate
+	return &date, aws.ToString(lastUsed.Region)
+}
+
 // CreateRole creates a new IAM role
 func (c *Client) CreateRole(ctx context.Context, roleName, trustPolicy, description string) error {
 	input := &iam.CreateRoleInput{
@@ -318,8 +372,70 @@ func processStringPolicy(policyStr string) (string, error) {
 	return string(bytes), nil
 }
 
-// Helper function to get role tags
-func (c *Client) getRoleTags(ctx context.Context, roleName string) (map[string]string, error) {
+// UpdateTrustPolicy replaces the trust policy of an existing role
+func (c *Client) UpdateTrustPolicy(ctx context.Context, roleName, trustPolicy string) error {
+	_, err := c.iam.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyDocument: aws.String(trustPolicy),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update trust policy of %s: %v", roleName, err)
+	}
+	return nil
+}
+
+// UpdateRoleDescription replaces the description of an existing role
+func (c *Client) UpdateRoleDescription(ctx context.Context, roleName, description string) error {
+	_, err := c.iam.UpdateRole(ctx, &iam.UpdateRoleInput{
+		RoleName:    aws.String(roleName),
+		Description: aws.String(description),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update description of %s: %v", roleName, err)
+	}
+	return nil
+}
+
+// UntagRole removes tags from a role
+func (c *Client) UntagRole(ctx context.Context, roleName string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := c.iam.UntagRole(ctx, &iam.UntagRoleInput{
+		RoleName: aws.String(roleName),
+		TagKeys:  keys,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to untag role %s: %v", roleName, err)
+	}
+	return nil
+}
+
+// CountRolePolicies returns the number of attached managed and inline policies of a role
+func (c *Client) CountRolePolicies(ctx context.Context, roleName string) (int, int, error) {
+	managed, err := c.getManagedPolicies(ctx, roleName)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list managed policies of %s: %v", roleName, err)
+	}
+
+	inline := 0
+	paginator := iam.NewListRolePoliciesPaginator(c.iam, &iam.ListRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to list inline policies of %s: %v", roleName, err)
+		}
+		inline += len(output.PolicyNames)
+	}
+
+	return len(managed), inline, nil
+}
+
+// GetRoleTags retrieves the tags of a role
+func (c *Client) GetRoleTags(ctx context.Context, roleName string) (map[string]string, error) {
 	tags := make(map[string]string)
 
 	output, err := c.iam.ListRoleTags(ctx, &iam.ListRoleTagsInput{
@@ -338,10 +454,16 @@ func (c *Client) getRoleTags(ctx context.Context, roleName string) (map[string]s
 
 // ReplacePatternInJSON replaces patterns in JSON strings
 func ReplacePatternInJSON(jsonStr, sourcePattern, destPattern string) string {
+	if sourcePattern == "" {
+		return jsonStr
+	}
 	return strings.ReplaceAll(jsonStr, sourcePattern, destPattern)
 }
 
 // GenerateNewRoleName generates new role name with pattern replacement
 func GenerateNewRoleName(originalName, sourcePattern, destPattern string) string {
+	if sourcePattern == "" {
+		return originalName
+	}
 	return strings.ReplaceAll(originalName, sourcePattern, destPattern)
 }
@@ -0,0 +1,90 @@
+// internal/aws/collisions.go - Destination role name collision analysis
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strategies for destination names that are already taken
+const (
+	CollisionFail   = "fail"
+	CollisionSkip   = "skip"
+	CollisionSuffix = "suffix"
+	CollisionSync   = "sync"
+)
+
+// Kinds of name collision
+const (
+	// CollisionBatch means an earlier role in the batch maps to the same name
+	CollisionBatch = "batch"
+	// CollisionExisting means the destination role already exists
+	CollisionExisting = "existing"
+)
+
+// NameCollision is a destination name that more than one role would get
+type NameCollision struct {
+	SourceRole string
+	DestRole   string
+	Kind       string
+	// Other is the earlier source role of a batch collision
+	Other string
+}
+
+func (c NameCollision) String() string {
+	if c.Kind == CollisionBatch {
+		return fmt.Sprintf("%s → %s: also the destination of %s", c.SourceRole, c.DestRole, c.Other)
+	}
+	return fmt.Sprintf("%s → %s: destination role already exists", c.SourceRole, c.DestRole)
+}
+
+// FindNameCollisions checks the destination names of a batch, in batch order,
+// against each other and against the roles already in the destination account.
+// IAM role names are unique case-insensitively, so names are compared that way.
+func FindNameCollisions(sources []string, destNames map[string]string, existing []string) []NameCollision {
+	taken := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		taken[strings.ToLower(name)] = true
+	}
+
+	var collisions []NameCollision
+	claimed := make(map[string]string)
+
+	for _, source := range sources {
+		dest := destNames[source]
+		key := strings.ToLower(dest)
+
+		if other, ok := claimed[key]; ok {
+			collisions = append(collisions, NameCollision{
+				SourceRole: source, DestRole: dest, Kind: CollisionBatch, Other: other,
+			})
+			continue
+		}
+		claimed[key] = source
+
+		if taken[key] {
+			collisions = append(collisions, NameCollision{
+				SourceRole: source, DestRole: dest, Kind: CollisionExisting,
+			})
+		}
+	}
+
+	return collisions
+}
+
+// SuffixRoleName returns name with the lowest "-N" suffix (from 2) that is not in
+// taken (lower-cased names), shortening name so the result fits maxLength
+func SuffixRoleName(name string, taken map[string]bool, maxLength int) string {
+	for n := 2; ; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		base := name
+		if maxLength > 0 && len(base)+len(suffix) > maxLength {
+			base = base[:maxLength-len(suffix)]
+		}
+
+		candidate := base + suffix
+		if !taken[strings.ToLower(candidate)] {
+			return candidate
+		}
+	}
+}
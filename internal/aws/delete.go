@@ -0,0 +1,141 @@
+// internal/aws/delete.go - Role deletion in the order IAM requires
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// DefaultProtectedRoles are name patterns never deleted by the tool
+var DefaultProtectedRoles = []string{
+	"AWSServiceRoleFor*",
+	"AWSReservedSSO_*",
+	"OrganizationAccountAccessRole",
+}
+
+// RoleDeletionPlan lists everything that must be removed before a role can be deleted
+type RoleDeletionPlan struct {
+	RoleName         string   `json:"role"`
+	InstanceProfiles []string `json:"instance_profiles,omitempty"`
+	ManagedPolicies  []string `json:"managed_policies,omitempty"`
+	InlinePolicies   []string `json:"inline_policies,omitempty"`
+}
+
+// IsProtectedRole reports whether a role name matches any protected pattern
+func IsProtectedRole(roleName string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if MatchWildcard(pattern, roleName) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanRoleDeletion collects the attachments of a role
+func (c *Client) PlanRoleDeletion(ctx context.Context, roleName string) (*RoleDeletionPlan, error) {
+	plan := &RoleDeletionPlan{RoleName: roleName}
+
+	profilePaginator := iam.NewListInstanceProfilesForRolePaginator(c.iam, &iam.ListInstanceProfilesForRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	for profilePaginator.HasMorePages() {
+		output, err := profilePaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instance profiles for %s: %v", roleName, err)
+		}
+		for _, profile := range output.InstanceProfiles {
+			plan.InstanceProfiles = append(plan.InstanceProfiles, aws.ToString(profile.InstanceProfileName))
+		}
+	}
+
+	var err error
+	plan.ManagedPolicies, err = c.getManagedPolicies(ctx, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed policies: %v", err)
+	}
+
+	policyPaginator := iam.NewListRolePoliciesPaginator(c.iam, &iam.ListRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	for policyPaginator.HasMorePages() {
+		output, err := policyPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list inline policies for %s: %v", roleName, err)
+		}
+		plan.InlinePolicies = append(plan.InlinePolicies, output.PolicyNames...)
+	}
+
+	return plan, nil
+}
+
+// DeleteRole removes a role from its instance profiles, detaches managed policies,
+// deletes inline policies and finally deletes the role. With deleteProfiles, the
+// instance profiles are deleted as well.
+func (c *Client) DeleteRole(ctx context.Context, plan *RoleDeletionPlan, deleteProfiles bool) error {
+	for _, profile := range plan.InstanceProfiles {
+		_, err := c.iam.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+			InstanceProfileName: aws.String(profile),
+			RoleName:            aws.String(plan.RoleName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove %s from instance profile %s: %v", plan.RoleName, profile, err)
+		}
+
+		if deleteProfiles {
+			_, err := c.iam.DeleteInstanceProfile(ctx, &iam.DeleteInstanceProfileInput{
+				InstanceProfileName: aws.String(profile),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete instance profile %s: %v", profile, err)
+			}
+		}
+	}
+
+	for _, policyArn := range plan.ManagedPolicies {
+		if err := c.DetachManagedPolicy(ctx, plan.RoleName, policyArn); err != nil {
+			return err
+		}
+	}
+
+	for _, policyName := range plan.InlinePolicies {
+		if err := c.DeleteInlinePolicy(ctx, plan.RoleName, policyName); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.iam.DeleteRole(ctx, &iam.DeleteRoleInput{
+		RoleName: aws.String(plan.RoleName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete role %s: %v", plan.RoleName, err)
+	}
+
+	return nil
+}
+
+// DetachManagedPolicy detaches a managed policy from a role
+func (c *Client) DetachManagedPolicy(ctx context.Context, roleName, policyArn string) error {
+	_, err := c.iam.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach policy %s from role %s: %v", policyArn, roleName, err)
+	}
+	return nil
+}
+
+// DeleteInlinePolicy deletes an inline policy from a role
+func (c *Client) DeleteInlinePolicy(ctx context.Context, roleName, policyName string) error {
+	_, err := c.iam.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete inline policy %s from role %s: %v", policyName, roleName, err)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+// internal/aws/dependencies.go - Roles that other roles pass, assume or trust
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Actions through which a role references another role
+var dependencyActions = []string{"iam:PassRole", ActionAssumeRole}
+
+// DependencyTrust is the Action of a reference from a role to a role its trust policy names
+const DependencyTrust = "trust"
+
+// RoleReference is a permission of one role to pass or assume another role, or
+// a role named as principal in another role's trust policy
+type RoleReference struct {
+	FromRole string `json:"from"`
+	ToRole   string `json:"to"`
+	Action   string `json:"action"`
+	// Policy is the inline policy name or managed policy ARN granting the permission,
+	// or "trust policy"
+	Policy string `json:"policy"`
+	// Resource is the ARN as written in the policy, or the trusted principal ARN
+	Resource string `json:"resource"`
+	// Wildcard is set when the resource matches role names by pattern
+	Wildcard bool `json:"wildcard,omitempty"`
+}
+
+func (r RoleReference) String() string {
+	text := fmt.Sprintf("%s → %s (%s via %s)", r.FromRole, r.ToRole, r.Action, r.Policy)
+	if r.Wildcard {
+		text += fmt.Sprintf(" [pattern %s]", r.Resource)
+	}
+	return text
+}
+
+// FindRoleReferences scans a role's policy documents, keyed by policy name or ARN,
+// for iam:PassRole and sts:AssumeRole grants on roles of accountID. Exact ARNs become
+// references to that role; ARNs with wildcards are matched against knownRoles.
+// Resource "*" is too broad to name a dependency and is ignored.
+func FindRoleReferences(roleName string, documents map[string]string, accountID string, knownRoles []string) ([]RoleReference, error) {
+	var references []RoleReference
+	seen := make(map[string]bool)
+
+	policies := make([]string, 0, len(documents))
+	for policy := range documents {
+		policies = append(policies, policy)
+	}
+	sort.Strings(policies)
+
+	for _, policyName := range policies {
+		policy, err := ParsePolicy(documents[policyName])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy %s: %v", policyName, err)
+		}
+
+		for _, statement := range policy.Statements {
+			if statement.NotResource {
+				continue
+			}
+
+			for _, action := range dependencyActions {
+				if !statement.AllowsAction(action) {
+					continue
+				}
+
+				for _, resource := range statement.Resources {
+					for _, target := range referencedRoles(resource, accountID, knownRoles) {
+						if target == roleName {
+							continue
+						}
+						key := target + "|" + action + "|" + policyName
+						if seen[key] {
+							continue
+						}
+						seen[key] = true
+
+						references = append(references, RoleReference{
+							FromRole: roleName,
+							ToRole:   target,
+							Action:   action,
+							Policy:   policyName,
+							Resource: resource,
+							Wildcard: strings.ContainsAny(resource, "*?"),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return references, nil
+}
+
+// FindTrustDependencies returns a reference from roleName to every role of
+// accountID that its trust policy names as an AWS principal. IAM rejects a trust
+// policy naming a role that does not exist, so those roles must be created first.
+func FindTrustDependencies(roleName, trustPolicy, accountID string) ([]RoleReference, error) {
+	policy, err := ParseTrustPolicy(trustPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trust policy: %v", err)
+	}
+
+	var references []RoleReference
+	for _, principal := range policy.Principals(PrincipalAWS) {
+		parts := strings.SplitN(principal, ":", 6)
+		if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" || parts[4] != accountID ||
+			!strings.HasPrefix(parts[5], "role/") {
+			continue
+		}
+
+		name := parts[5][strings.LastIndex(parts[5], "/")+1:]
+		if name == "" || name == roleName {
+			continue
+		}
+		references = append(references, RoleReference{
+			FromRole: roleName,
+			ToRole:   name,
+			Action:   DependencyTrust,
+			Policy:   "trust policy",
+			Resource: principal,
+		})
+	}
+
+	return references, nil
+}
+
+// Helper function to resolve a resource ARN to the role names it covers
+func referencedRoles(resource, accountID string, knownRoles []string) []string {
+	parts := strings.SplitN(resource, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" || parts[4] != accountID {
+		return nil
+	}
+	if !strings.HasPrefix(parts[5], "role/") {
+		return nil
+	}
+
+	// Role names are the last path segment; paths do not matter for matching
+	resourcePath := strings.TrimPrefix(parts[5], "role/")
+	name := resourcePath[strings.LastIndex(resourcePath, "/")+1:]
+	if name == "" || name == "*" {
+		return nil
+	}
+
+	if !strings.ContainsAny(name, "*?") {
+		return []string{name}
+	}
+
+	var matches []string
+	for _, role := range knownRoles {
+		if MatchWildcard(name, role) {
+			matches = append(matches, role)
+		}
+	}
+	return matches
+}
+
+// OrderingReferences returns the references that order a batch. IAM requires the
+// roles a trust policy names to exist, so trust references always count; a role a
+// policy passes or assumes need not exist, so a permission reference is dropped
+// when a trust reference runs the other way, as when deployer may assume worker
+// and worker trusts deployer.
+func OrderingReferences(references []RoleReference) []RoleReference {
+	trusted := make(map[string]bool)
+	for _, ref := range references {
+		if ref.Action == DependencyTrust {
+			trusted[ref.FromRole+"|"+ref.ToRole] = true
+		}
+	}
+
+	var ordering []RoleReference
+	for _, ref := range references {
+		if ref.Action != DependencyTrust && trusted[ref.ToRole+"|"+ref.FromRole] {
+			continue
+		}
+		ordering = append(ordering, ref)
+	}
+	return ordering
+}
+
+// DependencyOrder orders roles so that every role comes after the roles it
+// references. Roles keep their batch order where there is no constraint. Roles
+// in a reference cycle cannot be ordered; they are returned in batch order after
+// the rest, and each cycle is reported.
+func DependencyOrder(roles []string, references []RoleReference) ([]string, [][]string) {
+	inBatch := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		inBatch[role] = true
+	}
+
+	dependsOn := make(map[string]map[string]bool)
+	for _, ref := range references {
+		if !inBatch[ref.FromRole] || !inBatch[ref.ToRole] || ref.FromRole == ref.ToRole {
+			continue
+		}
+		if dependsOn[ref.FromRole] == nil {
+			dependsOn[ref.FromRole] = make(map[string]bool)
+		}
+		dependsOn[ref.FromRole][ref.ToRole] = true
+	}
+
+	var ordered []string
+	placed := make(map[string]bool)
+	for len(ordered) < len(roles) {
+		progress := false
+		for _, role := range roles {
+			if placed[role] {
+				continue
+			}
+			ready := true
+			for dep := range dependsOn[role] {
+				if !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, role)
+				placed[role] = true
+				progress = true
+				// Restart so earlier batch roles unblocked by this one go first
+				break
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+
+	var cycles [][]string
+	if len(ordered) < len(roles) {
+		var remaining []string
+		for _, role := range roles {
+			if !placed[role] {
+				remaining = append(remaining, role)
+			}
+		}
+		cycles = findCycles(remaining, dependsOn)
+		ordered = append(ordered, remaining...)
+	}
+
+	return ordered, cycles
+}
+
+// Helper function to list the strongly connected groups among unplaceable roles
+func findCycles(roles []string, dependsOn map[string]map[string]bool) [][]string {
+	reaches := func(from, to string) bool {
+		visited := map[string]bool{from: true}
+		queue := []string{from}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for next := range dependsOn[current] {
+				if next == to {
+					return true
+				}
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+		return false
+	}
+
+	var cycles [][]string
+	assigned := make(map[string]bool)
+	for _, role := range roles {
+		if assigned[role] || !reaches(role, role) {
+			continue
+		}
+		cycle := []string{role}
+		assigned[role] = true
+		for _, other := range roles {
+			if !assigned[other] && reaches(role, other) && reaches(other, role) {
+				cycle = append(cycle, other)
+				assigned[other] = true
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+)
+
+// deployer may assume worker, so worker's trust policy names deployer. Creating
+// worker fails unless deployer exists, whichever way the batch lists them, even
+// though the permission reference points the other way.
+func TestDependencyOrderAssumeRolePair(t *testing.T) {
+	const account = "111122223333"
+
+	deployerPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole",
+		"Resource":"arn:aws:iam::111122223333:role/worker"}]}`
+	workerTrust := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole",
+		"Principal":{"AWS":"arn:aws:iam::111122223333:role/deployer"}}]}`
+	deployerTrust := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole",
+		"Principal":{"Service":"lambda.amazonaws.com"}}]}`
+
+	// The permission points from deployer to worker, the opposite of the creation order
+	permissions, err := FindRoleReferences("deployer", map[string]string{"assume": deployerPolicy}, account, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(permissions) != 1 || permissions[0].ToRole != "worker" {
+		t.Fatalf("permission references = %v", permissions)
+	}
+
+	var trust []RoleReference
+	for role, document := range map[string]string{"deployer": deployerTrust, "worker": workerTrust} {
+		found, err := FindTrustDependencies(role, document, account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trust = append(trust, found...)
+	}
+
+	want := []RoleReference{{
+		FromRole: "worker",
+		ToRole:   "deployer",
+		Action:   DependencyTrust,
+		Policy:   "trust policy",
+		Resource: "arn:aws:iam::111122223333:role/deployer",
+	}}
+	if !reflect.DeepEqual(trust, want) {
+		t.Fatalf("trust references = %v, want %v", trust, want)
+	}
+
+	references := OrderingReferences(append(permissions, trust...))
+	for _, batch := range [][]string{{"worker", "deployer"}, {"deployer", "worker"}} {
+		ordered, cycles := DependencyOrder(batch, references)
+		if !reflect.DeepEqual(ordered, []string{"deployer", "worker"}) {
+			t.Errorf("DependencyOrder(%v) = %v, want deployer before worker", batch, ordered)
+		}
+		if len(cycles) != 0 {
+			t.Errorf("DependencyOrder(%v) reported cycles %v", batch, cycles)
+		}
+	}
+}
+
+func TestFindTrustDependenciesSkipsOtherPrincipals(t *testing.T) {
+	trust := `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":[
+			"111122223333",
+			"arn:aws:iam::111122223333:root",
+			"arn:aws:iam::111122223333:user/alice",
+			"arn:aws:iam::444455556666:role/other-account",
+			"arn:aws:iam::111122223333:role/service/with-path",
+			"arn:aws:iam::111122223333:role/self"]}},
+		{"Effect":"Deny","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::111122223333:role/denied"}}]}`
+
+	references, err := FindTrustDependencies("self", trust, "111122223333")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, ref := range references {
+		names = append(names, ref.ToRole)
+	}
+	if !reflect.DeepEqual(names, []string{"with-path"}) {
+		t.Errorf("dependencies = %v, want [with-path]", names)
+	}
+}
+
+// A deployer passing a task role to ECS pulls the task role in and clones it first
+func TestDependencyOrderPassRole(t *testing.T) {
+	deployerPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"iam:PassRole",
+		"Resource":"arn:aws:iam::111122223333:role/dev_ecs_task"}]}`
+
+	references, err := FindRoleReferences("dev_deployer", map[string]string{"deploy-policy": deployerPolicy},
+		"111122223333", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(references) != 1 || references[0].ToRole != "dev_ecs_task" || references[0].Action != "iam:PassRole" {
+		t.Fatalf("references = %v", references)
+	}
+
+	ordered, cycles := DependencyOrder([]string{"dev_deployer", "dev_ecs_task"}, OrderingReferences(references))
+	if !reflect.DeepEqual(ordered, []string{"dev_ecs_task", "dev_deployer"}) || len(cycles) != 0 {
+		t.Errorf("DependencyOrder = %v (cycles %v), want dev_ecs_task first", ordered, cycles)
+	}
+}
@@ -0,0 +1,190 @@
+// internal/aws/drift.go - Differences between a role's expected and actual state
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Drift change kinds, from the destination's point of view
+const (
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftChanged = "changed"
+)
+
+// DriftChange is one difference between the expected and actual destination role
+type DriftChange struct {
+	Kind string `json:"kind"`
+	// Resource is "trust policy", "managed policy", "inline policy" or "tag"
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// String formats the change for log output
+func (c DriftChange) String() string {
+	text := fmt.Sprintf("%s %s", c.Kind, c.Resource)
+	if c.Name != "" {
+		text += " " + c.Name
+	}
+	if c.Detail != "" {
+		text += ": " + c.Detail
+	}
+	return text
+}
+
+// DiffRoles compares the expected destination state with the actual role. Tags
+// whose keys start with ignoreTagPrefix are skipped.
+func DiffRoles(expected, actual *RoleInfo, ignoreTagPrefix string) ([]DriftChange, error) {
+	var changes []DriftChange
+
+	trustChanges, err := diffStatements("trust policy", "", expected.TrustPolicy, actual.TrustPolicy)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, trustChanges...)
+
+	expectedManaged := make(map[string]bool, len(expected.ManagedPolicies))
+	for _, arn := range expected.ManagedPolicies {
+		expectedManaged[arn] = true
+	}
+	actualManaged := make(map[string]bool, len(actual.ManagedPolicies))
+	for _, arn := range actual.ManagedPolicies {
+		actualManaged[arn] = true
+	}
+	for _, arn := range sortedSet(expectedManaged) {
+		if !actualManaged[arn] {
+			changes = append(changes, DriftChange{Kind: DriftRemoved, Resource: "managed policy", Name: arn})
+		}
+	}
+	for _, arn := range sortedSet(actualManaged) {
+		if !expectedManaged[arn] {
+			changes = append(changes, DriftChange{Kind: DriftAdded, Resource: "managed policy", Name: arn})
+		}
+	}
+
+	for _, name := range SortedKeys(expected.InlinePolicies) {
+		actualDocument, ok := actual.InlinePolicies[name]
+		if !ok {
+			changes = append(changes, DriftChange{Kind: DriftRemoved, Resource: "inline policy", Name: name})
+			continue
+		}
+		inlineChanges, err := diffStatements("inline policy", name, expected.InlinePolicies[name], actualDocument)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, inlineChanges...)
+	}
+	for _, name := range SortedKeys(actual.InlinePolicies) {
+		if _, ok := expected.InlinePolicies[name]; !ok {
+			changes = append(changes, DriftChange{Kind: DriftAdded, Resource: "inline policy", Name: name})
+		}
+	}
+
+	for _, key := range SortedKeys(expected.Tags) {
+		if ignoreTagPrefix != "" && strings.HasPrefix(key, ignoreTagPrefix) {
+			continue
+		}
+		actualValue, ok := actual.Tags[key]
+		switch {
+		case !ok:
+			changes = append(changes, DriftChange{Kind: DriftRemoved, Resource: "tag", Name: key, Detail: expected.Tags[key]})
+		case actualValue != expected.Tags[key]:
+			changes = append(changes, DriftChange{Kind: DriftChanged, Resource: "tag", Name: key,
+				Detail: fmt.Sprintf("%q, expected %q", actualValue, expected.Tags[key])})
+		}
+	}
+	for _, key := range SortedKeys(actual.Tags) {
+		if ignoreTagPrefix != "" && strings.HasPrefix(key, ignoreTagPrefix) {
+			continue
+		}
+		if _, ok := expected.Tags[key]; !ok {
+			changes = append(changes, DriftChange{Kind: DriftAdded, Resource: "tag", Name: key, Detail: actual.Tags[key]})
+		}
+	}
+
+	return changes, nil
+}
+
+// Helper function to diff two policy documents statement by statement. Statements
+// are compared in normalized form, so order and formatting do not count as drift.
+func diffStatements(resource, name, expected, actual string) ([]DriftChange, error) {
+	expectedStatements, err := normalizedStatements(expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected %s: %v", resource, err)
+	}
+	actualStatements, err := normalizedStatements(actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse actual %s: %v", resource, err)
+	}
+
+	remaining := make(map[string]int, len(actualStatements))
+	for _, statement := range actualStatements {
+		remaining[statement]++
+	}
+
+	var changes []DriftChange
+	for _, statement := range expectedStatements {
+		if remaining[statement] > 0 {
+			remaining[statement]--
+			continue
+		}
+		changes = append(changes, DriftChange{Kind: DriftRemoved, Resource: resource, Name: name,
+			Detail: "statement " + describeStatement(statement)})
+	}
+	for _, statement := range actualStatements {
+		if remaining[statement] > 0 {
+			remaining[statement]--
+			changes = append(changes, DriftChange{Kind: DriftAdded, Resource: resource, Name: name,
+				Detail: "statement " + describeStatement(statement)})
+		}
+	}
+
+	return changes, nil
+}
+
+// Helper function to normalize each statement of a policy document
+func normalizedStatements(document string) ([]string, error) {
+	statements, _, err := parseStatements(document)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		canonicalizePrincipals(statement)
+		data, err := json.Marshal(statement)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, string(data))
+	}
+	return normalized, nil
+}
+
+// Helper function to name a statement by its Sid, or show a short excerpt
+func describeStatement(statement string) string {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(statement), &raw); err == nil {
+		if sid := stringValue(raw["Sid"]); sid != "" {
+			return sid
+		}
+	}
+	if len(statement) > 120 {
+		return statement[:117] + "..."
+	}
+	return statement
+}
+
+// Helper function to list the keys of a set in a stable order
+func sortedSet(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,36 @@
+// internal/aws/errors.go - Classification of IAM API errors
+package aws
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+)
+
+// IsAccessDenied reports whether an IAM call failed for lack of permission
+func IsAccessDenied(err error) bool {
+	switch apiErrorCode(err) {
+	case "AccessDenied", "AccessDeniedException":
+		return true
+	}
+	return false
+}
+
+// IsEntityAlreadyExists reports whether err is IAM's EntityAlreadyExists error
+func IsEntityAlreadyExists(err error) bool {
+	return apiErrorCode(err) == "EntityAlreadyExists"
+}
+
+// IsNoSuchEntity reports whether err is IAM's NoSuchEntity error
+func IsNoSuchEntity(err error) bool {
+	return apiErrorCode(err) == "NoSuchEntity"
+}
+
+// Helper function to get the code of an API error anywhere in err's chain
+func apiErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestIsAccessDenied(t *testing.T) {
+	denied := &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"api error", denied, true},
+		{"wrapped", fmt.Errorf("failed to get tags: %w", fmt.Errorf("failed to get role x: %w", denied)), true},
+		{"other code", &smithy.GenericAPIError{Code: "NoSuchEntity"}, false},
+		{"code in message only", errors.New("role AccessDeniedRole not found"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAccessDenied(tt.err); got != tt.want {
+			t.Errorf("%s: IsAccessDenied = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
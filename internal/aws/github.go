@@ -0,0 +1,179 @@
+// internal/aws/github.go - GitHub Actions OIDC trust condition rewriting
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GitHubOIDCHost is the issuer of GitHub Actions OIDC tokens
+const GitHubOIDCHost = "token.actions.githubusercontent.com"
+
+// GitHubMapping holds explicit rules for rewriting GitHub Actions subject claims
+type GitHubMapping struct {
+	// Repos maps "owner/repo" (or just "owner") to the destination value
+	Repos map[string]string
+	// Environments maps GitHub deployment environment names
+	Environments map[string]string
+	// Branches maps branch names used in "ref:refs/heads/<branch>"
+	Branches map[string]string
+}
+
+// MapSubject rewrites a GitHub Actions "sub" claim such as
+// "repo:org/app:environment:dev" or "repo:org/app:ref:refs/heads/develop"
+func (m GitHubMapping) MapSubject(subject string) string {
+	if !strings.HasPrefix(subject, "repo:") {
+		return subject
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(subject, "repo:"), ":", 2)
+	repo := m.mapRepo(parts[0])
+	if len(parts) == 1 {
+		return "repo:" + repo
+	}
+
+	qualifier := parts[1]
+	switch {
+	case strings.HasPrefix(qualifier, "environment:"):
+		env := strings.TrimPrefix(qualifier, "environment:")
+		if dest, ok := m.Environments[env]; ok {
+			qualifier = "environment:" + dest
+		}
+
+	case strings.HasPrefix(qualifier, "ref:refs/heads/"):
+		branch := strings.TrimPrefix(qualifier, "ref:refs/heads/")
+		if dest, ok := m.Branches[branch]; ok {
+			qualifier = "ref:refs/heads/" + dest
+		}
+	}
+
+	return "repo:" + repo + ":" + qualifier
+}
+
+// mapRepo maps a full "owner/repo" first, then falls back to an owner-only rule
+func (m GitHubMapping) mapRepo(repo string) string {
+	if dest, ok := m.Repos[repo]; ok {
+		return dest
+	}
+
+	owner, name, found := strings.Cut(repo, "/")
+	if !found {
+		return repo
+	}
+	if dest, ok := m.Repos[owner]; ok && !strings.Contains(dest, "/") {
+		return dest + "/" + name
+	}
+
+	return repo
+}
+
+// RewriteGitHubTrust sets GitHub Actions subject conditions in a transformed trust
+// policy from the source policy and explicit mapping rules, so that generic pattern
+// replacement never edits them. It returns warnings for conditions that became
+// broader than the source or that pattern replacement would have changed.
+func RewriteGitHubTrust(sourcePolicy, transformedPolicy string, mapping GitHubMapping) (string, []string, error) {
+	if !strings.Contains(sourcePolicy, GitHubOIDCHost) {
+		return transformedPolicy, nil, nil
+	}
+
+	sourceStatements, _, err := parseStatements(sourcePolicy)
+	if err != nil {
+		return "", nil, err
+	}
+	destStatements, doc, err := parseStatements(transformedPolicy)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(sourceStatements) != len(destStatements) {
+		return "", nil, fmt.Errorf("trust policy statements changed during transformation")
+	}
+
+	var warnings []string
+	for i, item := range sourceStatements {
+		sourceConditions := conditionsOf(item)
+		destConditions := conditionsOf(destStatements[i])
+		if sourceConditions == nil || destConditions == nil {
+			continue
+		}
+
+		for operator, block := range sourceConditions {
+			entries, ok := block.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			destEntries, ok := destConditions[operator].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for key, value := range entries {
+				if key != GitHubOIDCHost+":sub" {
+					continue
+				}
+
+				mapped := mapPolicyValue(value, mapping.MapSubject)
+				sources := toStringSlice(value)
+				dests := toStringSlice(mapped)
+				patterned := toStringSlice(destEntries[key])
+
+				for j, source := range sources {
+					dest := dests[j]
+					if wildcardCount(dest) > wildcardCount(source) {
+						warnings = append(warnings, fmt.Sprintf(
+							"GitHub condition broadened: %s → %s", source, dest))
+					}
+
+					// Report values no rule touched but pattern replacement would have rewritten
+					if dest == source && j < len(patterned) && patterned[j] != source {
+						warnings = append(warnings, fmt.Sprintf(
+							"GitHub condition kept as %s (pattern replacement would give %s); add a GitHub mapping rule if needed",
+							source, patterned[j]))
+					}
+				}
+
+				destEntries[key] = mapped
+			}
+		}
+	}
+
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal trust policy: %v", err)
+	}
+
+	return string(bytes), warnings, nil
+}
+
+// Helper function to parse a policy and return its statements as a list
+func parseStatements(policy string) ([]interface{}, map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse policy: %v", err)
+	}
+
+	switch v := doc["Statement"].(type) {
+	case []interface{}:
+		return v, doc, nil
+	case map[string]interface{}:
+		return []interface{}{v}, doc, nil
+	}
+
+	return nil, doc, nil
+}
+
+// Helper function to get the Condition block of a statement
+func conditionsOf(statement interface{}) map[string]interface{} {
+	s, ok := statement.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	conditions, _ := s["Condition"].(map[string]interface{})
+	return conditions
+}
+
+// Helper function to count IAM wildcard characters in a condition value
+func wildcardCount(value string) int {
+	return strings.Count(value, "*") + strings.Count(value, "?")
+}
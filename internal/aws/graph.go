@@ -0,0 +1,316 @@
+// internal/aws/graph.go - Trust and permission graph of roles
+package aws
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Kinds of graph node
+const (
+	NodeRole      = "role"
+	NodeAccount   = "account"
+	NodePrincipal = "principal"
+	NodeService   = "service"
+	NodeFederated = "federated"
+	NodeWildcard  = "wildcard"
+)
+
+// Kinds of graph edge
+const (
+	// EdgeTrust means the source can assume the target role by its trust policy
+	EdgeTrust = "trust"
+	// EdgeAssume means the source role's policies allow assuming the target role
+	EdgeAssume = "assume"
+	// EdgePassRole means the source role's policies allow passing the target role
+	EdgePassRole = "passrole"
+)
+
+// GraphNode is a role or a principal trusted by a role
+type GraphNode struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Kind    string `json:"kind"`
+	Account string `json:"account,omitempty"`
+	// External is set for principals outside the graph's account
+	External bool `json:"external,omitempty"`
+}
+
+// GraphEdge is a trust relationship or a role reference in a policy
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+	// Via is the trust action or the policy granting the permission
+	Via string `json:"via,omitempty"`
+	// CrossAccount is set when the edge leaves the account or trusts anyone
+	CrossAccount bool `json:"cross_account,omitempty"`
+	// Conditional is set when the trust statement has conditions
+	Conditional bool `json:"conditional,omitempty"`
+}
+
+// Graph is the trust and permission graph of the roles of one account
+type Graph struct {
+	Account string       `json:"account"`
+	Nodes   []*GraphNode `json:"nodes"`
+	Edges   []*GraphEdge `json:"edges"`
+
+	nodes map[string]*GraphNode
+	edges map[string]bool
+}
+
+// NewGraph creates an empty graph for the roles of accountID
+func NewGraph(accountID string) *Graph {
+	return &Graph{
+		Account: accountID,
+		Nodes:   []*GraphNode{},
+		Edges:   []*GraphEdge{},
+		nodes:   make(map[string]*GraphNode),
+		edges:   make(map[string]bool),
+	}
+}
+
+// AddRole adds a role of the graph's account
+func (g *Graph) AddRole(roleName string) string {
+	id := "role:" + roleName
+	g.addNode(&GraphNode{ID: id, Label: roleName, Kind: NodeRole, Account: g.Account})
+	return id
+}
+
+// AddTrust adds an edge from every principal allowed by a role's trust policy
+func (g *Graph) AddTrust(roleName string, trust *TrustPolicy) {
+	roleID := g.AddRole(roleName)
+
+	for _, statement := range trust.AllowStatements() {
+		via := strings.Join(statement.Actions, ", ")
+		for _, principal := range statement.Principals {
+			node := g.principalNode(principal)
+			g.addNode(node)
+			g.addEdge(&GraphEdge{
+				From:         node.ID,
+				To:           roleID,
+				Kind:         EdgeTrust,
+				Via:          via,
+				CrossAccount: node.External || node.Kind == NodeWildcard,
+				Conditional:  len(statement.Conditions) > 0,
+			})
+		}
+	}
+}
+
+// AddReferences adds an edge for every role a role's policies may pass or assume
+func (g *Graph) AddReferences(references []RoleReference) {
+	for _, ref := range references {
+		kind := EdgeAssume
+		if ref.Action != ActionAssumeRole {
+			kind = EdgePassRole
+		}
+		g.addEdge(&GraphEdge{
+			From: g.AddRole(ref.FromRole),
+			To:   g.AddRole(ref.ToRole),
+			Kind: kind,
+			Via:  ref.Policy,
+		})
+	}
+}
+
+// Helper function to turn a trust principal into a node
+func (g *Graph) principalNode(principal Principal) *GraphNode {
+	switch principal.Type {
+	case PrincipalWildcard:
+		return &GraphNode{ID: "wildcard:*", Label: "* (anyone)", Kind: NodeWildcard, External: true}
+	case PrincipalService:
+		return &GraphNode{ID: "service:" + principal.Value, Label: principal.Value, Kind: NodeService}
+	case PrincipalFederated:
+		return &GraphNode{ID: "federated:" + principal.Value, Label: principal.Value, Kind: NodeFederated}
+	}
+
+	if principal.Value == "*" {
+		return &GraphNode{ID: "wildcard:*", Label: "* (anyone)", Kind: NodeWildcard, External: true}
+	}
+
+	account := principal.Account()
+	external := account != g.Account
+
+	// Account IDs and :root ARNs trust the whole account
+	parts := strings.SplitN(principal.Value, ":", 6)
+	if len(parts) != 6 || parts[5] == "root" {
+		return &GraphNode{ID: "account:" + account, Label: "account " + account,
+			Kind: NodeAccount, Account: account, External: external}
+	}
+
+	// Same-account roles join the role nodes of the graph
+	resource := parts[5]
+	if !external && strings.HasPrefix(resource, "role/") {
+		name := resource[strings.LastIndex(resource, "/")+1:]
+		return &GraphNode{ID: "role:" + name, Label: name, Kind: NodeRole, Account: account}
+	}
+
+	return &GraphNode{ID: "principal:" + principal.Value, Label: fmt.Sprintf("%s (%s)", resource, account),
+		Kind: NodePrincipal, Account: account, External: external}
+}
+
+// Helper function to add a node once
+func (g *Graph) addNode(node *GraphNode) {
+	if _, ok := g.nodes[node.ID]; ok {
+		return
+	}
+	g.nodes[node.ID] = node
+	g.Nodes = append(g.Nodes, node)
+}
+
+// Helper function to add an edge once
+func (g *Graph) addEdge(edge *GraphEdge) {
+	key := edge.From + "|" + edge.To + "|" + edge.Kind + "|" + edge.Via
+	if g.edges[key] {
+		return
+	}
+	g.edges[key] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// Sort orders nodes by ID and edges by their endpoints so output is stable
+func (g *Graph) Sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+}
+
+// CrossAccountEdges counts the edges that leave the account or trust anyone
+func (g *Graph) CrossAccountEdges() int {
+	count := 0
+	for _, edge := range g.Edges {
+		if edge.CrossAccount {
+			count++
+		}
+	}
+	return count
+}
+
+// Helper function to give every node a short identifier for DOT and Mermaid
+func (g *Graph) shortIDs() map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+// Helper function to label an edge
+func edgeLabel(edge *GraphEdge) string {
+	label := edge.Kind
+	if edge.Kind != EdgeTrust && edge.Via != "" {
+		label += ": " + edge.Via
+	}
+	if edge.Conditional {
+		label += " (conditional)"
+	}
+	return label
+}
+
+// WriteDOT renders the graph in Graphviz DOT. Cross-account edges and external
+// principals are drawn in red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	ids := g.shortIDs()
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph iam {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+
+	for _, node := range g.Nodes {
+		attrs := []string{"label=" + quote(node.Label)}
+		switch node.Kind {
+		case NodeRole:
+			attrs = append(attrs, "shape=box", "style=\"rounded,filled\"", "fillcolor=\"#dbeafe\"")
+		case NodeService:
+			attrs = append(attrs, "shape=ellipse", "style=filled", "fillcolor=\"#dcfce7\"")
+		case NodeFederated:
+			attrs = append(attrs, "shape=ellipse", "style=filled", "fillcolor=\"#fef9c3\"")
+		default:
+			attrs = append(attrs, "shape=ellipse")
+		}
+		if node.External {
+			attrs = append(attrs, "color=red", "fontcolor=red")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", ids[node.ID], strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		attrs := []string{"label=" + quote(edgeLabel(edge))}
+		switch edge.Kind {
+		case EdgePassRole:
+			attrs = append(attrs, "style=dashed")
+		case EdgeAssume:
+			attrs = append(attrs, "style=dotted")
+		}
+		if edge.CrossAccount {
+			attrs = append(attrs, "color=red", "fontcolor=red", "penwidth=2")
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", ids[edge.From], ids[edge.To], strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart. Cross-account edges and
+// external principals are drawn in red.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := g.shortIDs()
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	b.WriteString("  classDef role fill:#dbeafe,stroke:#1e40af\n")
+	b.WriteString("  classDef external stroke:#dc2626,color:#dc2626,stroke-width:2px\n")
+
+	for _, node := range g.Nodes {
+		shape := "(" + quote(node.Label) + ")"
+		if node.Kind == NodeRole {
+			shape = "[" + quote(node.Label) + "]"
+		}
+		fmt.Fprintf(&b, "  %s%s\n", ids[node.ID], shape)
+		if node.Kind == NodeRole {
+			fmt.Fprintf(&b, "  class %s role\n", ids[node.ID])
+		}
+		if node.External {
+			fmt.Fprintf(&b, "  class %s external\n", ids[node.ID])
+		}
+	}
+
+	var crossAccount []string
+	for i, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Kind != EdgeTrust {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[edge.From], arrow, quote(edgeLabel(edge)), ids[edge.To])
+		if edge.CrossAccount {
+			crossAccount = append(crossAccount, fmt.Sprintf("%d", i))
+		}
+	}
+	if len(crossAccount) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#dc2626,stroke-width:2px\n", strings.Join(crossAccount, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,212 @@
+// internal/aws/irsa.go - EKS IAM Roles for Service Accounts trust rewriting
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// IRSAMapping describes how IRSA trust relationships map to the destination
+type IRSAMapping struct {
+	// Providers maps source cluster OIDC IDs (or full provider host paths)
+	// to their destination counterparts
+	Providers map[string]string
+	// Namespaces maps source Kubernetes namespaces to destination namespaces
+	Namespaces map[string]string
+	// ServiceAccounts maps service account names (or "namespace:name") to new names
+	ServiceAccounts map[string]string
+}
+
+// IsEKSProvider reports whether an OIDC provider name belongs to an EKS cluster
+func IsEKSProvider(name string) bool {
+	return strings.HasPrefix(name, "oidc.eks.") && strings.Contains(name, ".amazonaws.com/id/")
+}
+
+// FindEKSProviders returns the EKS cluster OIDC providers trusted by a policy
+func FindEKSProviders(trustPolicy string) ([]string, error) {
+	arns, err := FindFederatedProviders(trustPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []string
+	for _, arn := range arns {
+		_, providerType, name, _ := ParseProviderArn(arn)
+		if providerType == ProviderTypeOIDC && IsEKSProvider(name) {
+			providers = append(providers, name)
+		}
+	}
+
+	return providers, nil
+}
+
+// MapProvider returns the destination provider host path for an EKS provider
+func (m IRSAMapping) MapProvider(name string) (string, bool) {
+	if dest, ok := m.Providers[name]; ok {
+		return dest, true
+	}
+
+	idx := strings.LastIndex(name, "/id/")
+	if idx < 0 {
+		return name, false
+	}
+
+	dest, ok := m.Providers[name[idx+len("/id/"):]]
+	if !ok {
+		return name, false
+	}
+
+	// A bare ID keeps the source host; a full host path replaces it
+	if strings.Contains(dest, "/id/") {
+		return dest, true
+	}
+	return name[:idx+len("/id/")] + dest, true
+}
+
+// mapSubject rewrites a "system:serviceaccount:<ns>:<sa>" subject
+func (m IRSAMapping) mapSubject(subject string) string {
+	if !strings.HasPrefix(subject, serviceAccountPrefix) {
+		return subject
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(subject, serviceAccountPrefix), ":", 2)
+	if len(parts) != 2 {
+		return subject
+	}
+	namespace, account := parts[0], parts[1]
+
+	if dest, ok := m.ServiceAccounts[namespace+":"+account]; ok {
+		if destParts := strings.SplitN(dest, ":", 2); len(destParts) == 2 {
+			return serviceAccountPrefix + dest
+		}
+		account = dest
+	} else if dest, ok := m.ServiceAccounts[account]; ok {
+		account = dest
+	}
+
+	if dest, ok := m.Namespaces[namespace]; ok {
+		namespace = dest
+	}
+
+	return serviceAccountPrefix + namespace + ":" + account
+}
+
+// RewriteIRSATrust rewrites EKS provider hosts, condition keys and service account
+// subjects in a trust policy. It returns the providers that had no mapping.
+func RewriteIRSATrust(trustPolicy string, mapping IRSAMapping) (string, []string, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(trustPolicy), &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse trust policy: %v", err)
+	}
+
+	var statements []interface{}
+	switch v := doc["Statement"].(type) {
+	case []interface{}:
+		statements = v
+	case map[string]interface{}:
+		statements = []interface{}{v}
+	}
+
+	unmapped := make(map[string]bool)
+	changed := false
+
+	for _, item := range statements {
+		statement, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Rewrite the Federated principal
+		if principal, ok := statement["Principal"].(map[string]interface{}); ok && principal["Federated"] != nil {
+			principal["Federated"] = mapPolicyValue(principal["Federated"], func(arn string) string {
+				account, providerType, name, ok := ParseProviderArn(arn)
+				if !ok || providerType != ProviderTypeOIDC || !IsEKSProvider(name) {
+					return arn
+				}
+
+				dest, mapped := mapping.MapProvider(name)
+				if !mapped {
+					unmapped[name] = true
+					return arn
+				}
+				changed = true
+				return fmt.Sprintf("arn:aws:iam::%s:%s/%s", account, ProviderTypeOIDC, dest)
+			})
+		}
+
+		// Rewrite condition keys and service account subjects
+		conditions, ok := statement["Condition"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for operator, block := range conditions {
+			entries, ok := block.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			rewritten := make(map[string]interface{}, len(entries))
+			for key, value := range entries {
+				newKey := key
+				if idx := strings.LastIndex(key, ":"); idx > 0 && IsEKSProvider(key[:idx]) {
+					if dest, mapped := mapping.MapProvider(key[:idx]); mapped {
+						newKey = dest + key[idx:]
+					} else {
+						unmapped[key[:idx]] = true
+					}
+
+					if strings.HasSuffix(key, ":sub") {
+						value = mapPolicyValue(value, mapping.mapSubject)
+					}
+				}
+
+				if newKey != key {
+					changed = true
+				}
+				rewritten[newKey] = value
+			}
+			conditions[operator] = rewritten
+		}
+	}
+
+	var missing []string
+	for name := range unmapped {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+
+	if !changed && len(mapping.Namespaces) == 0 && len(mapping.ServiceAccounts) == 0 {
+		return trustPolicy, missing, nil
+	}
+
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal trust policy: %v", err)
+	}
+
+	return string(bytes), missing, nil
+}
+
+// Helper function to apply a mapping to a string or list-of-strings policy value
+func mapPolicyValue(value interface{}, fn func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				result[i] = fn(s)
+			} else {
+				result[i] = item
+			}
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,132 @@
+// internal/aws/leakage.go - Post-transform scan for leftover source references
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Leak kinds reported by the scanner
+const (
+	LeakSourceToken   = "source-token"
+	LeakSourceAccount = "source-account"
+	LeakSourceArn     = "source-arn"
+)
+
+// LeakFinding is a source-environment reference left in a destination value
+type LeakFinding struct {
+	// Location names the document, e.g. "trust policy" or "inline policy app_access"
+	Location string `json:"location"`
+	// Path is a JSON path inside the document, or the field for names and tags
+	Path  string `json:"path"`
+	Kind  string `json:"kind"`
+	Match string `json:"match"`
+	Value string `json:"value"`
+}
+
+// String formats the finding for log output
+func (f LeakFinding) String() string {
+	return fmt.Sprintf("%s %s: %s '%s' in %q", f.Location, f.Path, f.Kind, f.Match, f.Value)
+}
+
+// LeakScanner looks for source-environment tokens and source account IDs
+type LeakScanner struct {
+	sourceAccount string
+	token         *regexp.Regexp
+}
+
+// NewLeakScanner builds a scanner for the source pattern and accounts. The
+// environment token is the pattern without separators (e.g. "dev_" → "dev") and
+// matches only as a whole word so "dev" does not flag "device".
+func NewLeakScanner(sourcePattern, destPattern, sourceAccount, destAccount string) *LeakScanner {
+	scanner := &LeakScanner{}
+	if sourceAccount != destAccount {
+		scanner.sourceAccount = sourceAccount
+	}
+
+	sourceToken := EnvironmentToken(sourcePattern)
+	if sourceToken != "" && !strings.EqualFold(sourceToken, EnvironmentToken(destPattern)) {
+		scanner.token = regexp.MustCompile(`(?i)(^|[^a-z0-9])(` + regexp.QuoteMeta(sourceToken) + `)([^a-z0-9]|$)`)
+	}
+
+	return scanner
+}
+
+// EnvironmentToken strips separators from a pattern, e.g. "dev_" → "dev"
+func EnvironmentToken(pattern string) string {
+	return strings.Trim(pattern, "_-./: ")
+}
+
+// ScanString checks a single name or tag value
+func (s *LeakScanner) ScanString(location, path, value string) []LeakFinding {
+	var findings []LeakFinding
+
+	if s.token != nil {
+		if match := s.token.FindStringSubmatch(value); match != nil {
+			findings = append(findings, LeakFinding{
+				Location: location, Path: path, Kind: LeakSourceToken, Match: match[2], Value: value,
+			})
+		}
+	}
+
+	if s.sourceAccount != "" && strings.Contains(value, s.sourceAccount) {
+		kind := LeakSourceAccount
+		if strings.HasPrefix(value, "arn:") {
+			kind = LeakSourceArn
+		}
+		findings = append(findings, LeakFinding{
+			Location: location, Path: path, Kind: kind, Match: s.sourceAccount, Value: value,
+		})
+	}
+
+	return findings
+}
+
+// ScanDocument checks every key and string value of a JSON policy document
+func (s *LeakScanner) ScanDocument(location, document string) ([]LeakFinding, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", location, err)
+	}
+
+	var findings []LeakFinding
+	s.walk(location, "$", doc, &findings)
+	return findings, nil
+}
+
+// Helper function to walk a decoded JSON value and scan strings with their paths
+func (s *LeakScanner) walk(location, path string, value interface{}, findings *[]LeakFinding) {
+	switch v := value.(type) {
+	case string:
+		*findings = append(*findings, s.ScanString(location, path, v)...)
+
+	case []interface{}:
+		for i, item := range v {
+			s.walk(location, fmt.Sprintf("%s[%d]", path, i), item, findings)
+		}
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := path + "." + key
+			if strings.ContainsAny(key, ".:/[] ") {
+				childPath = fmt.Sprintf("%s[%q]", path, key)
+			}
+
+			// Condition keys can carry source references too
+			for _, finding := range s.ScanString(location, childPath, key) {
+				finding.Value = key + " (key)"
+				*findings = append(*findings, finding)
+			}
+			s.walk(location, childPath, v[key], findings)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// internal/aws/limits.go - IAM quota and size validation
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// IAMLimits holds the IAM quotas checked before writing to the destination.
+// Defaults match the AWS documentation; the adjustable quotas can be raised
+// through Service Quotas, so callers may override them.
+type IAMLimits struct {
+	RoleNameLength         int
+	DescriptionLength      int
+	PolicyNameLength       int
+	TrustPolicySize        int // adjustable up to 4,096
+	InlinePolicyTotalSize  int
+	ManagedPolicySize      int
+	ManagedPoliciesPerRole int // adjustable up to 20
+	TagsPerRole            int
+	TagKeyLength           int
+	TagValueLength         int
+}
+
+// DefaultIAMLimits returns the default IAM quotas
+func DefaultIAMLimits() IAMLimits {
+	return IAMLimits{
+		RoleNameLength:         64,
+		DescriptionLength:      1000,
+		PolicyNameLength:       128,
+		TrustPolicySize:        2048,
+		InlinePolicyTotalSize:  10240,
+		ManagedPolicySize:      6144,
+		ManagedPoliciesPerRole: 10,
+		TagsPerRole:            50,
+		TagKeyLength:           128,
+		TagValueLength:         256,
+	}
+}
+
+// LimitViolation is a single quota a destination role would exceed
+type LimitViolation struct {
+	Limit  string `json:"limit"`
+	Detail string `json:"detail"`
+	Actual int    `json:"actual"`
+	Max    int    `json:"max"`
+}
+
+// String formats the violation for log output
+func (v LimitViolation) String() string {
+	return fmt.Sprintf("%s: %s (%d > %d)", v.Limit, v.Detail, v.Actual, v.Max)
+}
+
+// PolicySize returns the size IAM counts for a policy document, which excludes
+// insignificant whitespace
+func PolicySize(document string) int {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(document)); err != nil {
+		return utf8.RuneCountInString(document)
+	}
+	return utf8.RuneCount(compact.Bytes())
+}
+
+// ValidateRole checks a destination role against the limits and returns every violation
+func (l IAMLimits) ValidateRole(roleName, trustPolicy string, managedPolicies []string,
+	inlinePolicies map[string]string, tags map[string]string) []LimitViolation {
+
+	var violations []LimitViolation
+	add := func(limit, detail string, actual, max int) {
+		if actual > max {
+			violations = append(violations, LimitViolation{Limit: limit, Detail: detail, Actual: actual, Max: max})
+		}
+	}
+
+	add("role name length", roleName, utf8.RuneCountInString(roleName), l.RoleNameLength)
+	add("trust policy size", "characters excluding whitespace", PolicySize(trustPolicy), l.TrustPolicySize)
+	add("managed policies per role", "attached policies", len(managedPolicies), l.ManagedPoliciesPerRole)
+
+	policyNames := make([]string, 0, len(inlinePolicies))
+	for name := range inlinePolicies {
+		policyNames = append(policyNames, name)
+	}
+	sort.Strings(policyNames)
+
+	inlineTotal := 0
+	for _, name := range policyNames {
+		add("inline policy name length", name, utf8.RuneCountInString(name), l.PolicyNameLength)
+		inlineTotal += PolicySize(inlinePolicies[name])
+	}
+	add("inline policy total size", fmt.Sprintf("%d inline policies, excluding whitespace", len(inlinePolicies)),
+		inlineTotal, l.InlinePolicyTotalSize)
+
+	add("tags per role", "tags", len(tags), l.TagsPerRole)
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		add("tag key length", key, utf8.RuneCountInString(key), l.TagKeyLength)
+		add("tag value length", fmt.Sprintf("value of %s", key), utf8.RuneCountInString(tags[key]), l.TagValueLength)
+	}
+
+	return violations
+}
+
+// ValidateManagedPolicy checks a customer-managed policy the tool would create
+func (l IAMLimits) ValidateManagedPolicy(policyName, document string) []LimitViolation {
+	var violations []LimitViolation
+	if length := utf8.RuneCountInString(policyName); length > l.PolicyNameLength {
+		violations = append(violations, LimitViolation{
+			Limit: "managed policy name length", Detail: policyName, Actual: length, Max: l.PolicyNameLength,
+		})
+	}
+	if size := PolicySize(document); size > l.ManagedPolicySize {
+		violations = append(violations, LimitViolation{
+			Limit: "managed policy size", Detail: policyName, Actual: size, Max: l.ManagedPolicySize,
+		})
+	}
+	return violations
+}
+
+// ValidateDescription checks the role description length
+func (l IAMLimits) ValidateDescription(description string) []LimitViolation {
+	if length := utf8.RuneCountInString(description); length > l.DescriptionLength {
+		return []LimitViolation{{Limit: "description length", Detail: "role description", Actual: length, Max: l.DescriptionLength}}
+	}
+	return nil
+}
+
+// MinifyPolicy removes insignificant whitespace from a policy document
+func MinifyPolicy(document string) (string, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(document)); err != nil {
+		return "", fmt.Errorf("failed to minify policy: %v", err)
+	}
+	return compact.String(), nil
+}
+
+// SplitPolicy packs a policy's statements into as few minified documents as
+// possible, each no larger than maxSize. A single statement larger than maxSize
+// cannot be split and is an error.
+func SplitPolicy(document string, maxSize int) ([]string, error) {
+	statements, doc, err := parseStatements(document)
+	if err != nil {
+		return nil, err
+	}
+
+	render := func(group []interface{}) (string, error) {
+		part := make(map[string]interface{}, len(doc))
+		for key, value := range doc {
+			part[key] = value
+		}
+		part["Statement"] = group
+
+		data, err := json.Marshal(part)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal policy: %v", err)
+		}
+		return string(data), nil
+	}
+
+	var parts []string
+	var group []interface{}
+	current := ""
+
+	for i, statement := range statements {
+		candidate, err := render(append(group, statement))
+		if err != nil {
+			return nil, err
+		}
+
+		if PolicySize(candidate) <= maxSize {
+			group = append(group, statement)
+			current = candidate
+			continue
+		}
+
+		if len(group) == 0 {
+			return nil, fmt.Errorf("statement %d alone exceeds %d characters", i, maxSize)
+		}
+		parts = append(parts, current)
+
+		group = []interface{}{statement}
+		current, err = render(group)
+		if err != nil {
+			return nil, err
+		}
+		if PolicySize(current) > maxSize {
+			return nil, fmt.Errorf("statement %d alone exceeds %d characters", i, maxSize)
+		}
+	}
+
+	if len(group) > 0 {
+		parts = append(parts, current)
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,400 @@
+// internal/aws/policy.go - Permission policy parsing and IAM wildcard matching
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// PolicyStatement is one parsed statement of an identity-based policy
+type PolicyStatement struct {
+	Sid         string
+	Effect      string
+	Actions     []string
+	NotAction   bool
+	Resources   []string
+	NotResource bool
+	Conditions  []Condition
+}
+
+// Policy is a parsed identity-based policy document
+type Policy struct {
+	Statements []PolicyStatement
+}
+
+// ParsePolicy parses an identity-based policy document
+func ParsePolicy(document string) (*Policy, error) {
+	statements, _, err := parseStatements(document)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{}
+	for _, item := range statements {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		policy.Statements = append(policy.Statements, parsePolicyStatement(raw))
+	}
+
+	return policy, nil
+}
+
+// Helper function to parse one decoded statement of an identity-based policy
+func parsePolicyStatement(raw map[string]interface{}) PolicyStatement {
+	statement := PolicyStatement{
+		Sid:    stringValue(raw["Sid"]),
+		Effect: stringValue(raw["Effect"]),
+	}
+
+	if actions, found := raw["Action"]; found {
+		statement.Actions = toStringSlice(actions)
+	} else {
+		statement.Actions = toStringSlice(raw["NotAction"])
+		statement.NotAction = true
+	}
+
+	if resources, found := raw["Resource"]; found {
+		statement.Resources = toStringSlice(resources)
+	} else {
+		statement.Resources = toStringSlice(raw["NotResource"])
+		statement.NotResource = true
+	}
+
+	for operator, block := range conditionsOf(raw) {
+		entries, ok := block.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range entries {
+			statement.Conditions = append(statement.Conditions, Condition{
+				Operator: operator,
+				Key:      key,
+				Values:   toStringSlice(value),
+			})
+		}
+	}
+
+	return statement
+}
+
+// AllowsAction reports whether the statement grants the action (ignoring resources)
+func (s PolicyStatement) AllowsAction(action string) bool {
+	return s.Effect == "Allow" && s.coversAction(action)
+}
+
+// DeniesAction reports whether the statement denies the action (ignoring resources)
+func (s PolicyStatement) DeniesAction(action string) bool {
+	return s.Effect == "Deny" && s.coversAction(action)
+}
+
+// Helper function to match an action against Action or NotAction
+func (s PolicyStatement) coversAction(action string) bool {
+	matched := false
+	for _, pattern := range s.Actions {
+		if MatchAction(pattern, action) {
+			matched = true
+			break
+		}
+	}
+
+	if s.NotAction {
+		return !matched
+	}
+	return matched
+}
+
+// HasWildcardResource reports whether the statement applies to every resource
+func (s PolicyStatement) HasWildcardResource() bool {
+	if s.NotResource {
+		return true
+	}
+	for _, resource := range s.Resources {
+		if resource == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAction matches an IAM action against a pattern with * and ? wildcards.
+// Action names are case-insensitive.
+func MatchAction(pattern, action string) bool {
+	return MatchWildcard(strings.ToLower(pattern), strings.ToLower(action))
+}
+
+// MatchWildcard matches a value against an IAM-style pattern where * matches any
+// sequence of characters (including "/" and ":") and ? matches a single character
+func MatchWildcard(pattern, value string) bool {
+	p, v := 0, 0
+	starP, starV := -1, 0
+
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			starP, starV = p, v
+			p++
+		case starP >= 0:
+			// Let the last * absorb one more character and retry
+			starV++
+			p, v = starP+1, starV
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// NormalizePolicy renders a policy document in a canonical form (sorted keys,
+// no whitespace, principals as IAM stores them) so equivalent documents compare equal
+func NormalizePolicy(document string) (string, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse policy: %v", err)
+	}
+
+	if fields, ok := doc.(map[string]interface{}); ok {
+		switch statement := fields["Statement"].(type) {
+		case []interface{}:
+			for _, item := range statement {
+				canonicalizePrincipals(item)
+			}
+		case map[string]interface{}:
+			canonicalizePrincipals(statement)
+		}
+	}
+
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal policy: %v", err)
+	}
+	return string(normalized), nil
+}
+
+// canonicalizePrincipals rewrites the Principal and NotPrincipal blocks of a
+// decoded statement the way IAM stores them: an account ID becomes the account's
+// root ARN, several principals of a type are sorted, and a single one is a string
+func canonicalizePrincipals(statement interface{}) {
+	fields, ok := statement.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, key := range []string{"Principal", "NotPrincipal"} {
+		principals, ok := fields[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for principalType, value := range principals {
+			var values []string
+			switch v := value.(type) {
+			case string:
+				values = []string{v}
+			case []interface{}:
+				for _, item := range v {
+					values = append(values, stringValue(item))
+				}
+			default:
+				continue
+			}
+
+			for i, principal := range values {
+				if principalType == PrincipalAWS && accountFromPrincipal(principal) == principal {
+					values[i] = fmt.Sprintf("arn:aws:iam::%s:root", principal)
+				}
+			}
+			sort.Strings(values)
+
+			if len(values) == 1 {
+				principals[principalType] = values[0]
+				continue
+			}
+			list := make([]interface{}, len(values))
+			for i, principal := range values {
+				list[i] = principal
+			}
+			principals[principalType] = list
+		}
+	}
+}
+
+// PolicyHash returns the SHA-256 of the normalized policy document
+func PolicyHash(document string) (string, error) {
+	normalized, err := NormalizePolicy(document)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// GetManagedPolicyDocument retrieves the default version document of a managed policy
+func (c *Client) GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error) {
+	policyOutput, err := c.iam.GetPolicy(ctx, &iam.GetPolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get policy %s: %w", policyArn, err)
+	}
+
+	versionOutput, err := c.iam.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: policyOutput.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get policy version for %s: %w", policyArn, err)
+	}
+
+	return processPolicyDocument(versionOutput.PolicyVersion.Document)
+}
+
+// CreateManagedPolicy creates a customer-managed policy and returns its ARN
+func (c *Client) CreateManagedPolicy(ctx context.Context, policyName, policyDocument, description string) (string, error) {
+	input := &iam.CreatePolicyInput{
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(policyDocument),
+	}
+
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+
+	output, err := c.iam.CreatePolicy(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to create policy %s: %w", policyName, err)
+	}
+
+	return aws.ToString(output.Policy.Arn), nil
+}
+
+// Maximum number of versions IAM keeps for a managed policy
+const maxPolicyVersions = 5
+
+// UpdateManagedPolicy makes document the default version of an existing policy,
+// deleting the oldest non-default version when the policy already has the
+// maximum number of versions. It returns false when the default version already
+// has the same content.
+func (c *Client) UpdateManagedPolicy(ctx context.Context, policyArn, document string) (bool, error) {
+	current, err := c.GetManagedPolicyDocument(ctx, policyArn)
+	if err != nil {
+		return false, err
+	}
+	currentHash, err := PolicyHash(current)
+	if err != nil {
+		return false, err
+	}
+	wantedHash, err := PolicyHash(document)
+	if err != nil {
+		return false, err
+	}
+	if currentHash == wantedHash {
+		return false, nil
+	}
+
+	versions, err := c.iam.ListPolicyVersions(ctx, &iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list versions of %s: %v", policyArn, err)
+	}
+
+	if len(versions.Versions) >= maxPolicyVersions {
+		var oldest *types.PolicyVersion
+		for i := range versions.Versions {
+			version := &versions.Versions[i]
+			if version.IsDefaultVersion {
+				continue
+			}
+			if oldest == nil || aws.ToTime(version.CreateDate).Before(aws.ToTime(oldest.CreateDate)) {
+				oldest = version
+			}
+		}
+
+		if oldest != nil {
+			_, err := c.iam.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{
+				PolicyArn: aws.String(policyArn),
+				VersionId: oldest.VersionId,
+			})
+			if err != nil {
+				return false, fmt.Errorf("failed to delete version %s of %s: %v", aws.ToString(oldest.VersionId), policyArn, err)
+			}
+		}
+	}
+
+	_, err = c.iam.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+		PolicyArn:      aws.String(policyArn),
+		PolicyDocument: aws.String(document),
+		SetAsDefault:   true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create a new version of %s: %v", policyArn, err)
+	}
+
+	return true, nil
+}
+
+// ManagedPolicyState is what undo checks before deleting a policy the tool created
+type ManagedPolicyState struct {
+	AttachmentCount int
+	VersionCount    int
+	Document        string
+}
+
+// DescribeManagedPolicy returns the attachment count, version count and default document of a policy
+func (c *Client) DescribeManagedPolicy(ctx context.Context, policyArn string) (*ManagedPolicyState, error) {
+	policyOutput, err := c.iam.GetPolicy(ctx, &iam.GetPolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy %s: %w", policyArn, err)
+	}
+
+	versions, err := c.iam.ListPolicyVersions(ctx, &iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s: %w", policyArn, err)
+	}
+
+	document, err := c.GetManagedPolicyDocument(ctx, policyArn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ManagedPolicyState{
+		AttachmentCount: int(aws.ToInt32(policyOutput.Policy.AttachmentCount)),
+		VersionCount:    len(versions.Versions),
+		Document:        document,
+	}, nil
+}
+
+// DeleteManagedPolicy deletes a customer-managed policy with a single version
+func (c *Client) DeleteManagedPolicy(ctx context.Context, policyArn string) error {
+	_, err := c.iam.DeletePolicy(ctx, &iam.DeletePolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete policy %s: %v", policyArn, err)
+	}
+	return nil
+}
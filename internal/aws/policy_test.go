@@ -0,0 +1,32 @@
+package aws
+
+import "testing"
+
+// IAM stores an account ID principal as the account's root ARN, so a trust
+// policy read back after CreateRole must hash like the one that was written
+func TestNormalizePolicyCanonicalizesPrincipals(t *testing.T) {
+	written := `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"sts:AssumeRole",
+		"Principal":{"AWS":["123456789012"],"Service":["lambda.amazonaws.com","ec2.amazonaws.com"]}}}`
+	stored := `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"sts:AssumeRole",
+		"Principal":{"AWS":"arn:aws:iam::123456789012:root","Service":["ec2.amazonaws.com","lambda.amazonaws.com"]}}}`
+
+	writtenHash, err := PolicyHash(written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storedHash, err := PolicyHash(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if writtenHash != storedHash {
+		written, _ := NormalizePolicy(written)
+		stored, _ := NormalizePolicy(stored)
+		t.Errorf("hashes differ:\n  %s\n  %s", written, stored)
+	}
+
+	other := `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"sts:AssumeRole",
+		"Principal":{"AWS":"arn:aws:iam::210987654321:root","Service":["ec2.amazonaws.com","lambda.amazonaws.com"]}}}`
+	if otherHash, _ := PolicyHash(other); otherHash == storedHash {
+		t.Error("different accounts hash the same")
+	}
+}
@@ -0,0 +1,195 @@
+// internal/aws/privesc.go - Privilege-escalation detection in role policies
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EscalationRule describes a known IAM privilege-escalation primitive. A rule
+// matches when every action in Actions is allowed by the role's policies.
+type EscalationRule struct {
+	ID          string
+	Severity    string
+	Description string
+	Actions     []string
+	// WildcardResource requires the first action to be allowed on "*"
+	WildcardResource bool
+}
+
+// EscalationRules is the built-in rule catalog
+var EscalationRules = []EscalationRule{
+	{ID: "full-admin", Severity: SeverityCritical, Description: "Allows every action on every resource",
+		Actions: []string{"*:*"}, WildcardResource: true},
+	{ID: "create-policy-version", Severity: SeverityCritical, Description: "Can publish a new default version of any managed policy",
+		Actions: []string{"iam:CreatePolicyVersion"}},
+	{ID: "set-default-policy-version", Severity: SeverityHigh, Description: "Can roll a managed policy back to a more permissive version",
+		Actions: []string{"iam:SetDefaultPolicyVersion"}},
+	{ID: "attach-role-policy", Severity: SeverityCritical, Description: "Can attach any managed policy to a role",
+		Actions: []string{"iam:AttachRolePolicy"}},
+	{ID: "attach-user-policy", Severity: SeverityCritical, Description: "Can attach any managed policy to a user",
+		Actions: []string{"iam:AttachUserPolicy"}},
+	{ID: "attach-group-policy", Severity: SeverityCritical, Description: "Can attach any managed policy to a group",
+		Actions: []string{"iam:AttachGroupPolicy"}},
+	{ID: "put-role-policy", Severity: SeverityCritical, Description: "Can write inline policies on roles",
+		Actions: []string{"iam:PutRolePolicy"}},
+	{ID: "put-user-policy", Severity: SeverityCritical, Description: "Can write inline policies on users",
+		Actions: []string{"iam:PutUserPolicy"}},
+	{ID: "update-assume-role-policy", Severity: SeverityHigh, Description: "Can change who may assume roles",
+		Actions: []string{"iam:UpdateAssumeRolePolicy"}},
+	{ID: "create-access-key", Severity: SeverityHigh, Description: "Can create access keys for other users",
+		Actions: []string{"iam:CreateAccessKey"}},
+	{ID: "login-profile", Severity: SeverityHigh, Description: "Can set console passwords for other users",
+		Actions: []string{"iam:UpdateLoginProfile"}},
+	{ID: "passrole-wildcard", Severity: SeverityHigh, Description: "Can pass any role to a service",
+		Actions: []string{"iam:PassRole"}, WildcardResource: true},
+	{ID: "lambda-passrole", Severity: SeverityHigh, Description: "Can run code as another role through a new Lambda function",
+		Actions: []string{"iam:PassRole", "lambda:CreateFunction"}},
+	{ID: "ec2-passrole", Severity: SeverityHigh, Description: "Can run code as another role through a new EC2 instance",
+		Actions: []string{"iam:PassRole", "ec2:RunInstances"}},
+	{ID: "cloudformation-passrole", Severity: SeverityHigh, Description: "Can create resources as another role through CloudFormation",
+		Actions: []string{"iam:PassRole", "cloudformation:CreateStack"}},
+	{ID: "glue-passrole", Severity: SeverityMedium, Description: "Can run code as another role through a Glue dev endpoint",
+		Actions: []string{"iam:PassRole", "glue:CreateDevEndpoint"}},
+	{ID: "assume-any-role", Severity: SeverityHigh, Description: "Can assume any role that trusts this account",
+		Actions: []string{"sts:AssumeRole"}, WildcardResource: true},
+}
+
+// EscalationFinding is a rule that matched a role's policies
+type EscalationFinding struct {
+	Role        string   `json:"role"`
+	RuleID      string   `json:"rule"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Policies    []string `json:"policies"`
+}
+
+// String formats the finding for log output
+func (f EscalationFinding) String() string {
+	return fmt.Sprintf("[%s] %s: %s (via %s)", f.Severity, f.RuleID, f.Description, strings.Join(f.Policies, ", "))
+}
+
+// AnalyzeEscalation runs the rule catalog against a role's policy documents.
+// documents maps a policy name (inline name or managed ARN) to its JSON.
+func AnalyzeEscalation(roleName string, documents map[string]string) ([]EscalationFinding, error) {
+	names := make([]string, 0, len(documents))
+	for name := range documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// An explicit Deny in any policy of the role overrides every Allow
+	policies := make(map[string]*Policy, len(documents))
+	var denies []PolicyStatement
+	for _, name := range names {
+		policy, err := ParsePolicy(documents[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy %s: %v", name, err)
+		}
+		policies[name] = policy
+
+		for _, statement := range policy.Statements {
+			if statement.Effect == "Deny" {
+				denies = append(denies, statement)
+			}
+		}
+	}
+
+	var findings []EscalationFinding
+	for _, rule := range EscalationRules {
+		var sources []string
+		matched := true
+
+		for i, action := range rule.Actions {
+			source := ""
+			for _, name := range names {
+				if policyAllows(policies[name], denies, action, i == 0 && rule.WildcardResource) {
+					source = name
+					break
+				}
+			}
+			if source == "" {
+				matched = false
+				break
+			}
+			sources = append(sources, source)
+		}
+
+		if matched {
+			findings = append(findings, EscalationFinding{
+				Role:        roleName,
+				RuleID:      rule.ID,
+				Severity:    rule.Severity,
+				Description: rule.Description,
+				Policies:    Dedupe(sources),
+			})
+
+			// Full admin implies every other rule; don't repeat them
+			if rule.ID == "full-admin" {
+				break
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// Helper function to check whether any Allow statement grants an action that
+// the role's Deny statements do not take away again
+func policyAllows(policy *Policy, denies []PolicyStatement, action string, wildcardResource bool) bool {
+	for _, statement := range policy.Statements {
+		if !statement.AllowsAction(action) {
+			continue
+		}
+		if wildcardResource && !statement.HasWildcardResource() {
+			continue
+		}
+		if deniedEverywhere(statement, denies, action) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Helper function to check whether an unconditional Deny of the action covers
+// every resource an Allow statement grants. A conditional Deny may not apply, so
+// it does not count.
+func deniedEverywhere(allow PolicyStatement, denies []PolicyStatement, action string) bool {
+	for _, deny := range denies {
+		if !deny.DeniesAction(action) || len(deny.Conditions) > 0 || deny.NotResource {
+			continue
+		}
+		if denyCoversResources(deny, allow) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to check whether a Deny's resources include all of an Allow's
+func denyCoversResources(deny, allow PolicyStatement) bool {
+	for _, pattern := range deny.Resources {
+		if pattern == "*" {
+			return true
+		}
+	}
+	if allow.NotResource || len(allow.Resources) == 0 {
+		return false
+	}
+
+	for _, resource := range allow.Resources {
+		covered := false
+		for _, pattern := range deny.Resources {
+			if MatchWildcard(pattern, resource) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package aws
+
+import "testing"
+
+func TestAnalyzeEscalationHonoursExplicitDeny(t *testing.T) {
+	iamAll := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"iam:*","Resource":"*"}]}`
+
+	tests := []struct {
+		name      string
+		documents map[string]string
+		passRole  bool
+	}{
+		{
+			name:      "allow only",
+			documents: map[string]string{"iam-all": iamAll},
+			passRole:  true,
+		},
+		{
+			name: "deny in another policy",
+			documents: map[string]string{
+				"iam-all":     iamAll,
+				"no-passrole": `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"iam:PassRole","Resource":"*"}]}`,
+			},
+		},
+		{
+			name: "deny in the same policy",
+			documents: map[string]string{
+				"iam-all": `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"iam:*","Resource":"*"},
+					{"Effect":"Deny","Action":"iam:Pass*","Resource":"*"}]}`,
+			},
+		},
+		{
+			name: "deny on some roles only",
+			documents: map[string]string{
+				"iam-all":     iamAll,
+				"no-passrole": `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"iam:PassRole","Resource":"arn:aws:iam::*:role/admin*"}]}`,
+			},
+			passRole: true,
+		},
+		{
+			name: "conditional deny",
+			documents: map[string]string{
+				"iam-all": iamAll,
+				"no-passrole": `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"iam:PassRole","Resource":"*",
+					"Condition":{"StringNotEquals":{"iam:PassedToService":"lambda.amazonaws.com"}}}]}`,
+			},
+			passRole: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := AnalyzeEscalation("role", tt.documents)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rules := make(map[string]bool)
+			for _, finding := range findings {
+				rules[finding.RuleID] = true
+			}
+
+			if rules["passrole-wildcard"] != tt.passRole {
+				t.Errorf("passrole-wildcard = %v, want %v (findings: %v)", rules["passrole-wildcard"], tt.passRole, findings)
+			}
+			// The rest of iam:* is still allowed
+			if !rules["attach-role-policy"] {
+				t.Errorf("attach-role-policy missing (findings: %v)", findings)
+			}
+		})
+	}
+}
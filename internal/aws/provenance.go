@@ -0,0 +1,94 @@
+// internal/aws/provenance.go - Provenance tags that link cloned roles to their source
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Provenance tag keys written on cloned roles
+const (
+	ProvenanceTagPrefix        = "iam-role-cloner:"
+	ProvenanceTagSourceAccount = ProvenanceTagPrefix + "source-account"
+	ProvenanceTagSourceRoleArn = ProvenanceTagPrefix + "source-role-arn"
+	ProvenanceTagContentHash   = ProvenanceTagPrefix + "content-hash"
+	ProvenanceTagToolVersion   = ProvenanceTagPrefix + "tool-version"
+	ProvenanceTagRunID         = ProvenanceTagPrefix + "run-id"
+)
+
+// Provenance records where a cloned role came from
+type Provenance struct {
+	SourceAccount string `json:"source_account"`
+	SourceRoleArn string `json:"source_role_arn"`
+	// ContentHash is RoleContentHash of the source role at clone time
+	ContentHash string `json:"content_hash"`
+	ToolVersion string `json:"tool_version"`
+	RunID       string `json:"run_id"`
+}
+
+// Tags returns the provenance as role tags
+func (p Provenance) Tags() map[string]string {
+	return map[string]string{
+		ProvenanceTagSourceAccount: p.SourceAccount,
+		ProvenanceTagSourceRoleArn: p.SourceRoleArn,
+		ProvenanceTagContentHash:   p.ContentHash,
+		ProvenanceTagToolVersion:   p.ToolVersion,
+		ProvenanceTagRunID:         p.RunID,
+	}
+}
+
+// ParseProvenance reads provenance tags; ok is false when the role has none
+func ParseProvenance(tags map[string]string) (Provenance, bool) {
+	p := Provenance{
+		SourceAccount: tags[ProvenanceTagSourceAccount],
+		SourceRoleArn: tags[ProvenanceTagSourceRoleArn],
+		ContentHash:   tags[ProvenanceTagContentHash],
+		ToolVersion:   tags[ProvenanceTagToolVersion],
+		RunID:         tags[ProvenanceTagRunID],
+	}
+	return p, p.SourceRoleArn != ""
+}
+
+// SourceRoleName returns the source role name from its ARN (without the path)
+func (p Provenance) SourceRoleName() string {
+	return p.SourceRoleArn[strings.LastIndex(p.SourceRoleArn, "/")+1:]
+}
+
+// RoleContentHash hashes the permission-relevant content of a role: the trust
+// policy, attached managed policy ARNs and inline policy documents. Tags and the
+// description are left out so retagging a source role does not count as a change.
+func RoleContentHash(info *RoleInfo) (string, error) {
+	trustPolicy, err := NormalizePolicy(info.TrustPolicy)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize trust policy: %v", err)
+	}
+
+	managedPolicies := append([]string{}, info.ManagedPolicies...)
+	sort.Strings(managedPolicies)
+
+	inlinePolicies := make(map[string]string, len(info.InlinePolicies))
+	for name, document := range info.InlinePolicies {
+		normalized, err := NormalizePolicy(document)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalize inline policy %s: %v", name, err)
+		}
+		inlinePolicies[name] = normalized
+	}
+
+	// Map keys are marshaled in sorted order, so the encoding is stable
+	content, err := json.Marshal(map[string]interface{}{
+		"TrustPolicy":     trustPolicy,
+		"ManagedPolicies": managedPolicies,
+		"InlinePolicies":  inlinePolicies,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode role content: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,216 @@
+// internal/aws/providers.go - OIDC and SAML identity provider operations
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// Identity provider kinds as they appear in provider ARNs
+const (
+	ProviderTypeOIDC = "oidc-provider"
+	ProviderTypeSAML = "saml-provider"
+)
+
+// IdentityProvider holds everything needed to recreate a federated provider
+type IdentityProvider struct {
+	Arn  string
+	Type string
+	// Name is the part of the ARN after the type, e.g. the OIDC host/path
+	// or the SAML provider name
+	Name string
+
+	// OIDC settings
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+
+	// SAML settings
+	SAMLMetadata string
+}
+
+// ParseProviderArn splits an identity provider ARN into account, type and name
+func ParseProviderArn(arn string) (account, providerType, name string, ok bool) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" {
+		return "", "", "", false
+	}
+
+	resource := strings.SplitN(parts[5], "/", 2)
+	if len(resource) != 2 {
+		return "", "", "", false
+	}
+
+	if resource[0] != ProviderTypeOIDC && resource[0] != ProviderTypeSAML {
+		return "", "", "", false
+	}
+
+	return parts[4], resource[0], resource[1], true
+}
+
+// FindFederatedProviders returns the provider ARNs used as Federated principals
+func FindFederatedProviders(trustPolicy string) ([]string, error) {
+	policy, err := ParseTrustPolicy(trustPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []string
+	for _, value := range policy.Principals(PrincipalFederated) {
+		if _, _, _, ok := ParseProviderArn(value); ok {
+			providers = append(providers, value)
+		}
+	}
+
+	return providers, nil
+}
+
+// RewriteProviderArns points provider ARNs in a policy at the destination account
+func RewriteProviderArns(policy, sourceAccount, destAccount string) string {
+	if sourceAccount == "" || destAccount == "" || sourceAccount == destAccount {
+		return policy
+	}
+
+	for _, providerType := range []string{ProviderTypeOIDC, ProviderTypeSAML} {
+		policy = strings.ReplaceAll(policy,
+			fmt.Sprintf("arn:aws:iam::%s:%s/", sourceAccount, providerType),
+			fmt.Sprintf("arn:aws:iam::%s:%s/", destAccount, providerType))
+	}
+
+	return policy
+}
+
+// GetIdentityProvider retrieves the configuration of an OIDC or SAML provider
+func (c *Client) GetIdentityProvider(ctx context.Context, arn string) (*IdentityProvider, error) {
+	_, providerType, name, ok := ParseProviderArn(arn)
+	if !ok {
+		return nil, fmt.Errorf("not an identity provider ARN: %s", arn)
+	}
+
+	provider := &IdentityProvider{
+		Arn:  arn,
+		Type: providerType,
+		Name: name,
+	}
+
+	switch providerType {
+	case ProviderTypeOIDC:
+		output, err := c.iam.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: aws.String(arn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OIDC provider %s: %w", arn, err)
+		}
+
+		provider.URL = aws.ToString(output.Url)
+		provider.ClientIDs = output.ClientIDList
+		provider.Thumbprints = output.ThumbprintList
+
+	case ProviderTypeSAML:
+		output, err := c.iam.GetSAMLProvider(ctx, &iam.GetSAMLProviderInput{
+			SAMLProviderArn: aws.String(arn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get SAML provider %s: %w", arn, err)
+		}
+
+		provider.SAMLMetadata = aws.ToString(output.SAMLMetadataDocument)
+	}
+
+	return provider, nil
+}
+
+// IdentityProviderExists checks if an OIDC or SAML provider exists. Errors other
+// than NoSuchEntity are returned, so a denied lookup is not mistaken for a missing provider.
+func (c *Client) IdentityProviderExists(ctx context.Context, arn string) (bool, error) {
+	_, err := c.GetIdentityProvider(ctx, arn)
+	if err == nil {
+		return true, nil
+	}
+	if IsNoSuchEntity(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// CreateIdentityProvider recreates a provider in this client's account and returns its ARN
+func (c *Client) CreateIdentityProvider(ctx context.Context, provider *IdentityProvider) (string, error) {
+	switch provider.Type {
+	case ProviderTypeOIDC:
+		providerURL := provider.URL
+		if providerURL == "" {
+			providerURL = provider.Name
+		}
+		if !strings.HasPrefix(providerURL, "https://") {
+			providerURL = "https://" + providerURL
+		}
+
+		output, err := c.iam.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			Url:            aws.String(providerURL),
+			ClientIDList:   provider.ClientIDs,
+			ThumbprintList: provider.Thumbprints,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create OIDC provider %s: %v", providerURL, err)
+		}
+		return aws.ToString(output.OpenIDConnectProviderArn), nil
+
+	case ProviderTypeSAML:
+		output, err := c.iam.CreateSAMLProvider(ctx, &iam.CreateSAMLProviderInput{
+			Name:                 aws.String(provider.Name),
+			SAMLMetadataDocument: aws.String(provider.SAMLMetadata),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create SAML provider %s: %v", provider.Name, err)
+		}
+		return aws.ToString(output.SAMLProviderArn), nil
+	}
+
+	return "", fmt.Errorf("unsupported provider type: %s", provider.Type)
+}
+
+// DeleteIdentityProvider deletes an OIDC or SAML provider
+func (c *Client) DeleteIdentityProvider(ctx context.Context, arn string) error {
+	_, providerType, _, ok := ParseProviderArn(arn)
+	if !ok {
+		return fmt.Errorf("not an identity provider ARN: %s", arn)
+	}
+
+	var err error
+	switch providerType {
+	case ProviderTypeOIDC:
+		_, err = c.iam.DeleteOpenIDConnectProvider(ctx, &iam.DeleteOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: aws.String(arn),
+		})
+	case ProviderTypeSAML:
+		_, err = c.iam.DeleteSAMLProvider(ctx, &iam.DeleteSAMLProviderInput{
+			SAMLProviderArn: aws.String(arn),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete identity provider %s: %v", arn, err)
+	}
+
+	return nil
+}
+
+// Helper function to normalize a policy value that may be a string or a list
+func toStringSlice(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
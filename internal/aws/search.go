@@ -0,0 +1,221 @@
+// internal/aws/search.go - Find roles by action, resource or principal
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Where a search match was found
+const (
+	SearchTrust   = "trust"
+	SearchInline  = "inline"
+	SearchManaged = "managed"
+)
+
+// SearchQuery describes what a role must allow to match. Actions and resources
+// are matched together against Allow statements of permission policies, principals
+// against Allow statements of the trust policy. Empty fields match anything; a
+// role matches when every non-empty kind of criterion is met by some statement.
+type SearchQuery struct {
+	// Actions are IAM actions, optionally with * and ? wildcards
+	Actions []string
+	// Resources are resource ARNs, optionally with * and ? wildcards
+	Resources []string
+	// Principals are account IDs, principal ARNs, service names or providers, optionally with wildcards
+	Principals []string
+}
+
+// HasPermissionCriteria reports whether the query looks at permission policies
+func (q SearchQuery) HasPermissionCriteria() bool {
+	return len(q.Actions) > 0 || len(q.Resources) > 0
+}
+
+// SearchMatch is a statement that matched a search
+type SearchMatch struct {
+	Role   string `json:"role"`
+	Source string `json:"source"`
+	// Policy is the inline policy name or managed policy ARN; empty for trust
+	Policy string `json:"policy,omitempty"`
+	Sid    string `json:"sid,omitempty"`
+	// Matched lists the query terms the statement matched
+	Matched   []string        `json:"matched"`
+	Statement json.RawMessage `json:"statement"`
+}
+
+// SearchRole returns the statements of a role that match the query, or nothing
+// when the role as a whole does not match. inline is keyed by policy name and
+// managed by policy ARN.
+func SearchRole(roleName, trustPolicy string, inline, managed map[string]string, query SearchQuery) ([]SearchMatch, error) {
+	var permissionMatches, trustMatches []SearchMatch
+
+	if query.HasPermissionCriteria() {
+		for _, group := range []struct {
+			source    string
+			documents map[string]string
+		}{{SearchInline, inline}, {SearchManaged, managed}} {
+			names := make([]string, 0, len(group.documents))
+			for name := range group.documents {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				matches, err := searchPolicy(roleName, group.source, name, group.documents[name], query)
+				if err != nil {
+					return nil, err
+				}
+				permissionMatches = append(permissionMatches, matches...)
+			}
+		}
+		if len(permissionMatches) == 0 {
+			return nil, nil
+		}
+	}
+
+	if len(query.Principals) > 0 {
+		statements, _, err := parseStatements(trustPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse trust policy: %v", err)
+		}
+		for _, item := range statements {
+			raw, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			statement := parseTrustStatement(raw)
+			if statement.Effect != "Allow" || statement.NotPrincipal {
+				continue
+			}
+
+			var matched []string
+			for _, term := range query.Principals {
+				for _, principal := range statement.Principals {
+					if principalMatches(principal, term) {
+						matched = append(matched, "principal "+term)
+						break
+					}
+				}
+			}
+			if len(matched) > 0 {
+				trustMatches = append(trustMatches, newSearchMatch(roleName, SearchTrust, "", statement.Sid, matched, raw))
+			}
+		}
+		if len(trustMatches) == 0 {
+			return nil, nil
+		}
+	}
+
+	return append(permissionMatches, trustMatches...), nil
+}
+
+// Helper function to match the Allow statements of one permission policy
+func searchPolicy(roleName, source, policyName, document string, query SearchQuery) ([]SearchMatch, error) {
+	statements, _, err := parseStatements(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %v", policyName, err)
+	}
+
+	var matches []SearchMatch
+	for _, item := range statements {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		statement := parsePolicyStatement(raw)
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		var matched []string
+		if len(query.Actions) > 0 {
+			for _, action := range query.Actions {
+				if statementMatchesAction(statement, action) {
+					matched = append(matched, "action "+action)
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+		}
+
+		if len(query.Resources) > 0 {
+			found := false
+			for _, resource := range query.Resources {
+				if statementMatchesResource(statement, resource) {
+					matched = append(matched, "resource "+resource)
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+
+		matches = append(matches, newSearchMatch(roleName, source, policyName, statement.Sid, matched, raw))
+	}
+
+	return matches, nil
+}
+
+// Helper function to match a queried action, which may itself be a pattern
+func statementMatchesAction(statement PolicyStatement, action string) bool {
+	matched := false
+	for _, pattern := range statement.Actions {
+		if MatchAction(pattern, action) || MatchAction(action, pattern) {
+			matched = true
+			break
+		}
+	}
+	if statement.NotAction {
+		return !matched
+	}
+	return matched
+}
+
+// Helper function to match a queried resource, which may itself be a pattern
+func statementMatchesResource(statement PolicyStatement, resource string) bool {
+	matched := false
+	for _, pattern := range statement.Resources {
+		if MatchWildcard(pattern, resource) || MatchWildcard(resource, pattern) {
+			matched = true
+			break
+		}
+	}
+	if statement.NotResource {
+		return !matched
+	}
+	return matched
+}
+
+// Helper function to match a trust principal. An account ID matches every
+// principal of that account, and a wildcard principal matches everything.
+func principalMatches(principal Principal, term string) bool {
+	if principal.Type == PrincipalWildcard || principal.Value == "*" {
+		return true
+	}
+	if accountFromPrincipal(term) == term {
+		return principal.Account() == term
+	}
+
+	value, term := strings.ToLower(principal.Value), strings.ToLower(term)
+	return MatchWildcard(term, value) || MatchWildcard(value, term)
+}
+
+// Helper function to build a match with the statement as written
+func newSearchMatch(roleName, source, policyName, sid string, matched []string, raw map[string]interface{}) SearchMatch {
+	statement, err := json.Marshal(raw)
+	if err != nil {
+		statement = []byte("null")
+	}
+	return SearchMatch{
+		Role:      roleName,
+		Source:    source,
+		Policy:    policyName,
+		Sid:       sid,
+		Matched:   matched,
+		Statement: statement,
+	}
+}
@@ -0,0 +1,711 @@
+// internal/aws/selector.go - Role selector expressions shared by list and clone
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// RoleSummary is the role metadata selectors match against. Tags and LastUsed
+// are only present after LoadRoleDetails.
+type RoleSummary struct {
+	Name        string
+	Path        string
+	Arn         string
+	CreateDate  time.Time
+	TrustPolicy string
+	// MaxSessionDuration is in seconds
+	MaxSessionDuration int32
+
+	DetailsLoaded bool
+	Tags          map[string]string
+	// LastUsed is nil when the role has never been used
+	LastUsed       *time.Time
+	LastUsedRegion string
+}
+
+// ListRoleSummaries lists the roles whose name starts with prefix
+func (c *Client) ListRoleSummaries(ctx context.Context, prefix string) ([]*RoleSummary, error) {
+	var roles []*RoleSummary
+
+	paginator := iam.NewListRolesPaginator(c.iam, &iam.ListRolesInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list roles: %v", err)
+		}
+
+		for _, role := range output.Roles {
+			roleName := aws.ToString(role.RoleName)
+			if prefix != "" && !strings.HasPrefix(roleName, prefix) {
+				continue
+			}
+
+			trustPolicy, err := processPolicyDocument(role.AssumeRolePolicyDocument)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process trust policy of %s: %v", roleName, err)
+			}
+
+			roles = append(roles, &RoleSummary{
+				Name:        roleName,
+				Path:        aws.ToString(role.Path),
+				Arn:         aws.ToString(role.Arn),
+				CreateDate:  aws.ToTime(role.CreateDate),
+				TrustPolicy: trustPolicy,
+
+				MaxSessionDuration: aws.ToInt32(role.MaxSessionDuration),
+			})
+		}
+	}
+
+	return roles, nil
+}
+
+// LoadRoleDetails fetches the tags and last-used date that ListRoles omits
+func (c *Client) LoadRoleDetails(ctx context.Context, role *RoleSummary) error {
+	output, err := c.iam.GetRole(ctx, &iam.GetRoleInput{
+		RoleName: aws.String(role.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get role %s: %w", role.Name, err)
+	}
+
+	role.Tags = make(map[string]string)
+	for _, tag := range output.Role.Tags {
+		role.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	role.LastUsed, role.LastUsedRegion = lastUsedOf(output.Role.RoleLastUsed)
+	role.DetailsLoaded = true
+
+	return nil
+}
+
+// LoadRoleDetailsAll runs LoadRoleDetails for many roles with at most parallelism
+// roles in flight. The returned errors are in the order of roles.
+func (c *Client) LoadRoleDetailsAll(ctx context.Context, roles []*RoleSummary, parallelism int, onDone func()) []error {
+	errs := make([]error, len(roles))
+	forEachConcurrently(len(roles), parallelism, func(i int) {
+		errs[i] = c.LoadRoleDetails(ctx, roles[i])
+	}, onDone)
+	return errs
+}
+
+// roleMatcher is one node of a parsed selector expression
+type roleMatcher interface {
+	match(role *RoleSummary, now time.Time) bool
+	needsDetails() bool
+}
+
+// RoleSelector matches roles against an expression plus include and exclude lists.
+// A role is selected when it matches the expression, any include (if there are
+// includes) and no exclude.
+type RoleSelector struct {
+	expr    roleMatcher
+	include []roleMatcher
+	exclude []roleMatcher
+	// Now is the reference time for age and last-used terms
+	Now time.Time
+}
+
+// NewRoleSelector parses a selector expression and include/exclude lists,
+// each entry of which is itself an expression.
+//
+// Terms are combined with "and" (or juxtaposition), "or" and "not", and grouped
+// with parentheses. Supported terms:
+//
+//	dev_*                  name glob (case-insensitive), same as name:dev_*
+//	name~'^dev_(a|b)$'     name regex (quote values containing spaces or parentheses)
+//	path:/service-role/    path prefix
+//	tag:Team               tag exists
+//	tag:Team=payments      tag value (glob)
+//	trust:service          trust principal type (service, aws, federated, wildcard)
+//	trust:service=ec2.*    trust principal of that type matching a glob
+//	created<2024-01-01     creation date (<, <=, >, >=)
+//	age>90d                time since creation (units: h, d, w)
+//	lastused>90d           time since last use; never-used roles always match >
+//	lastused:never         roles without a recorded use
+func NewRoleSelector(expression string, include, exclude []string) (*RoleSelector, error) {
+	selector := &RoleSelector{Now: time.Now()}
+
+	if strings.TrimSpace(expression) != "" {
+		expr, err := parseSelector(expression)
+		if err != nil {
+			return nil, err
+		}
+		selector.expr = expr
+	}
+
+	for _, entry := range include {
+		expr, err := parseSelector(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include %q: %v", entry, err)
+		}
+		selector.include = append(selector.include, expr)
+	}
+
+	for _, entry := range exclude {
+		expr, err := parseSelector(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude %q: %v", entry, err)
+		}
+		selector.exclude = append(selector.exclude, expr)
+	}
+
+	return selector, nil
+}
+
+// RequireNameContaining narrows the selector to role names containing text,
+// ignoring case. Unlike RequireName, "*" and "?" in text match literally.
+func (s *RoleSelector) RequireNameContaining(text string) {
+	name := nameSubstringMatcher(strings.ToLower(text))
+	if s.expr == nil {
+		s.expr = name
+		return
+	}
+	s.expr = andMatcher{s.expr, name}
+}
+
+// IsEmpty reports whether the selector matches every role
+func (s *RoleSelector) IsEmpty() bool {
+	return s == nil || (s.expr == nil && len(s.include) == 0 && len(s.exclude) == 0)
+}
+
+// NeedsDetails reports whether any term reads tags or last-used dates
+func (s *RoleSelector) NeedsDetails() bool {
+	if s.IsEmpty() {
+		return false
+	}
+	if s.expr != nil && s.expr.needsDetails() {
+		return true
+	}
+	for _, m := range append(append([]roleMatcher{}, s.include...), s.exclude...) {
+		if m.needsDetails() {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether a role is selected
+func (s *RoleSelector) Match(role *RoleSummary) bool {
+	if s.IsEmpty() {
+		return true
+	}
+
+	if s.expr != nil && !s.expr.match(role, s.Now) {
+		return false
+	}
+
+	if len(s.include) > 0 {
+		included := false
+		for _, m := range s.include {
+			if m.match(role, s.Now) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, m := range s.exclude {
+		if m.match(role, s.Now) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the selected roles in their original order
+func (s *RoleSelector) Filter(roles []*RoleSummary) []*RoleSummary {
+	var selected []*RoleSummary
+	for _, role := range roles {
+		if s.Match(role) {
+			selected = append(selected, role)
+		}
+	}
+	return selected
+}
+
+// parseSelector parses one selector expression
+func parseSelector(expression string) (roleMatcher, error) {
+	tokens, err := tokenizeSelector(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+
+	p := &selectorParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in selector", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// Helper function to split an expression into terms, operators and parentheses
+func tokenizeSelector(expression string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	quote := rune(0)
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expression {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		case r == '!' && current.Len() == 0:
+			tokens = append(tokens, "!")
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in selector")
+	}
+	flush()
+
+	return tokens, nil
+}
+
+type selectorParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *selectorParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *selectorParser) parseOr() (roleMatcher, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []roleMatcher{left}
+	for isOr(p.peek()) {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return orMatcher(terms), nil
+}
+
+func (p *selectorParser) parseAnd() (roleMatcher, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []roleMatcher{left}
+	for {
+		next := p.peek()
+		if next == "" || next == ")" || isOr(next) {
+			break
+		}
+		if isAnd(next) {
+			p.pos++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return andMatcher(terms), nil
+}
+
+func (p *selectorParser) parseNot() (roleMatcher, error) {
+	next := p.peek()
+	if next == "!" || strings.EqualFold(next, "not") {
+		p.pos++
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notMatcher{inner}, nil
+	}
+
+	if next == "(" {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ) in selector")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	if next == "" || next == ")" || isAnd(next) || isOr(next) {
+		return nil, fmt.Errorf("expected a term in selector, got %q", next)
+	}
+
+	p.pos++
+	return parseSelectorTerm(next)
+}
+
+// Helper function to recognize the or operator
+func isOr(token string) bool {
+	return token == "||" || strings.EqualFold(token, "or")
+}
+
+// Helper function to recognize the and operator
+func isAnd(token string) bool {
+	return token == "&&" || strings.EqualFold(token, "and")
+}
+
+// parseSelectorTerm parses a single term such as tag:Team=payments or age>90d
+func parseSelectorTerm(term string) (roleMatcher, error) {
+	lower := strings.ToLower(term)
+
+	switch {
+	case strings.HasPrefix(lower, "name~"):
+		re, err := regexp.Compile(term[len("name~"):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex in %q: %v", term, err)
+		}
+		return nameRegexMatcher{re}, nil
+
+	case strings.HasPrefix(lower, "name:"):
+		return nameGlobMatcher(strings.ToLower(term[len("name:"):])), nil
+
+	case strings.HasPrefix(lower, "path:"):
+		return pathMatcher(term[len("path:"):]), nil
+
+	case strings.HasPrefix(lower, "tag:"):
+		key, value, hasValue := strings.Cut(term[len("tag:"):], "=")
+		if key == "" {
+			return nil, fmt.Errorf("missing tag key in %q", term)
+		}
+		return tagMatcher{key: key, value: value, hasValue: hasValue}, nil
+
+	case strings.HasPrefix(lower, "trust:"):
+		kind, value, _ := strings.Cut(term[len("trust:"):], "=")
+		principalType, ok := trustPrincipalTypes[strings.ToLower(kind)]
+		if !ok {
+			return nil, fmt.Errorf("unknown trust principal type %q (use service, aws, federated or wildcard)", kind)
+		}
+		return trustMatcher{principalType: principalType, value: value}, nil
+
+	case lower == "lastused:never":
+		return neverUsedMatcher{}, nil
+
+	case hasComparison(lower, "created"):
+		op, operand, _ := splitComparison(term[len("created"):])
+		date, err := time.Parse("2006-01-02", operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in %q (use YYYY-MM-DD)", term)
+		}
+		return createdMatcher{op: op, date: date}, nil
+
+	case hasComparison(lower, "age"):
+		op, operand, _ := splitComparison(term[len("age"):])
+		age, err := ParseAge(operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid term %q: %v", term, err)
+		}
+		return ageMatcher{op: op, age: age}, nil
+
+	case hasComparison(lower, "lastused"):
+		op, operand, _ := splitComparison(term[len("lastused"):])
+		age, err := ParseAge(operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid term %q: %v", term, err)
+		}
+		return lastUsedMatcher{op: op, age: age}, nil
+	}
+
+	if strings.ContainsAny(term, ":~<>") {
+		return nil, fmt.Errorf("unknown selector term %q", term)
+	}
+	return nameGlobMatcher(lower), nil
+}
+
+var trustPrincipalTypes = map[string]string{
+	"service":   PrincipalService,
+	"aws":       PrincipalAWS,
+	"federated": PrincipalFederated,
+	"wildcard":  PrincipalWildcard,
+}
+
+// Helper function to detect a comparison term such as age>90d
+func hasComparison(term, field string) bool {
+	if !strings.HasPrefix(term, field) {
+		return false
+	}
+	_, _, err := splitComparison(term[len(field):])
+	return err == nil
+}
+
+// Helper function to split "<=30d" into operator and operand
+func splitComparison(rest string) (string, string, error) {
+	for _, op := range []string{"<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			return op, rest[len(op):], nil
+		}
+	}
+	return "", "", fmt.Errorf("expected <, <=, > or >=")
+}
+
+// ParseAge parses a duration such as 90d, 2w or 12h
+func ParseAge(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 90d, 2w or 12h)", value)
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 90d, 2w or 12h)", value)
+	}
+
+	switch value[len(value)-1] {
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("invalid age %q (use e.g. 90d, 2w or 12h)", value)
+}
+
+// Helper function to apply a comparison operator to two durations or times
+func compare(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// Helper function to compare two durations
+func compareDurations(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+type andMatcher []roleMatcher
+
+func (m andMatcher) match(role *RoleSummary, now time.Time) bool {
+	for _, term := range m {
+		if !term.match(role, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m andMatcher) needsDetails() bool {
+	for _, term := range m {
+		if term.needsDetails() {
+			return true
+		}
+	}
+	return false
+}
+
+type orMatcher []roleMatcher
+
+func (m orMatcher) match(role *RoleSummary, now time.Time) bool {
+	for _, term := range m {
+		if term.match(role, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m orMatcher) needsDetails() bool {
+	return andMatcher(m).needsDetails()
+}
+
+type notMatcher struct{ inner roleMatcher }
+
+func (m notMatcher) match(role *RoleSummary, now time.Time) bool {
+	return !m.inner.match(role, now)
+}
+
+func (m notMatcher) needsDetails() bool { return m.inner.needsDetails() }
+
+// nameGlobMatcher holds a lower-cased glob; IAM role names are case-insensitive
+type nameGlobMatcher string
+
+func (m nameGlobMatcher) match(role *RoleSummary, _ time.Time) bool {
+	return MatchWildcard(string(m), strings.ToLower(role.Name))
+}
+
+func (m nameGlobMatcher) needsDetails() bool { return false }
+
+// nameSubstringMatcher holds lower-cased text that role names must contain
+type nameSubstringMatcher string
+
+func (m nameSubstringMatcher) match(role *RoleSummary, _ time.Time) bool {
+	return strings.Contains(strings.ToLower(role.Name), string(m))
+}
+
+func (m nameSubstringMatcher) needsDetails() bool { return false }
+
+type nameRegexMatcher struct{ re *regexp.Regexp }
+
+func (m nameRegexMatcher) match(role *RoleSummary, _ time.Time) bool {
+	return m.re.MatchString(role.Name)
+}
+
+func (m nameRegexMatcher) needsDetails() bool { return false }
+
+type pathMatcher string
+
+func (m pathMatcher) match(role *RoleSummary, _ time.Time) bool {
+	return strings.HasPrefix(role.Path, string(m))
+}
+
+func (m pathMatcher) needsDetails() bool { return false }
+
+type tagMatcher struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+func (m tagMatcher) match(role *RoleSummary, _ time.Time) bool {
+	value, ok := role.Tags[m.key]
+	if !ok {
+		return false
+	}
+	return !m.hasValue || MatchWildcard(m.value, value)
+}
+
+func (m tagMatcher) needsDetails() bool { return true }
+
+type trustMatcher struct {
+	principalType string
+	value         string
+}
+
+func (m trustMatcher) match(role *RoleSummary, _ time.Time) bool {
+	trust, err := ParseTrustPolicy(role.TrustPolicy)
+	if err != nil {
+		return false
+	}
+
+	if m.principalType == PrincipalWildcard {
+		return trust.HasWildcardPrincipal()
+	}
+
+	for _, principal := range trust.Principals(m.principalType) {
+		if m.value == "" || MatchWildcard(m.value, principal) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m trustMatcher) needsDetails() bool { return false }
+
+type createdMatcher struct {
+	op   string
+	date time.Time
+}
+
+// The date is a whole UTC day, so a role created during it is equal to it
+func (m createdMatcher) match(role *RoleSummary, _ time.Time) bool {
+	created := role.CreateDate.UTC()
+	day := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+	return compare(m.op, day.Compare(m.date))
+}
+
+func (m createdMatcher) needsDetails() bool { return false }
+
+type ageMatcher struct {
+	op  string
+	age time.Duration
+}
+
+func (m ageMatcher) match(role *RoleSummary, now time.Time) bool {
+	return compare(m.op, compareDurations(now.Sub(role.CreateDate), m.age))
+}
+
+func (m ageMatcher) needsDetails() bool { return false }
+
+type lastUsedMatcher struct {
+	op  string
+	age time.Duration
+}
+
+func (m lastUsedMatcher) match(role *RoleSummary, now time.Time) bool {
+	if role.LastUsed == nil {
+		// Never used is older than any age
+		return m.op == ">" || m.op == ">="
+	}
+	return compare(m.op, compareDurations(now.Sub(*role.LastUsed), m.age))
+}
+
+func (m lastUsedMatcher) needsDetails() bool { return true }
+
+type neverUsedMatcher struct{}
+
+func (m neverUsedMatcher) match(role *RoleSummary, _ time.Time) bool {
+	return role.LastUsed == nil
+}
+
+func (m neverUsedMatcher) needsDetails() bool { return true }
@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var selectorNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// Helper function to build the roles the selector tests run against
+func selectorTestRoles() []*RoleSummary {
+	daysAgo := func(days int) *time.Time {
+		t := selectorNow.AddDate(0, 0, -days)
+		return &t
+	}
+
+	return []*RoleSummary{
+		{
+			Name:        "dev_api",
+			Path:        "/",
+			CreateDate:  time.Date(2024, 1, 1, 15, 30, 0, 0, time.UTC),
+			TrustPolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"Service":"lambda.amazonaws.com"}}]}`,
+			Tags:        map[string]string{"Team": "payments"},
+			LastUsed:    daysAgo(10),
+		},
+		{
+			Name:        "dev_worker",
+			Path:        "/service-role/",
+			CreateDate:  time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			TrustPolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::111122223333:role/dev_api"}}]}`,
+			Tags:        map[string]string{"Team": "search"},
+		},
+		{
+			Name:        "prod_api",
+			Path:        "/",
+			CreateDate:  time.Date(2025, 5, 30, 8, 0, 0, 0, time.UTC),
+			TrustPolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"arn:aws:iam::111122223333:oidc-provider/token.actions.githubusercontent.com"}}]}`,
+			Tags:        map[string]string{},
+			LastUsed:    daysAgo(200),
+		},
+	}
+}
+
+// Helper function to list the names of the roles a selector picks
+func selectedNames(selector *RoleSelector) []string {
+	var names []string
+	for _, role := range selector.Filter(selectorTestRoles()) {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func TestRoleSelectorExpressions(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"dev_*", []string{"dev_api", "dev_worker"}},
+		{"DEV_*", []string{"dev_api", "dev_worker"}},
+		{"name:*_api", []string{"dev_api", "prod_api"}},
+		{"dev_* and tag:Team=payments", []string{"dev_api"}},
+		{"dev_* tag:Team=pay*", []string{"dev_api"}},
+		{"prod_api or dev_worker", []string{"dev_worker", "prod_api"}},
+		{"prod_api || path:/service-role/", []string{"dev_worker", "prod_api"}},
+		{"not dev_*", []string{"prod_api"}},
+		{"!dev_*", []string{"prod_api"}},
+		{"NOT (dev_api OR prod_api)", []string{"dev_worker"}},
+		{"dev_* && !tag:Team=search", []string{"dev_api"}},
+		// and binds tighter than or
+		{"dev_api or dev_worker and tag:Team=search", []string{"dev_api", "dev_worker"}},
+		{"(dev_api or dev_worker) and tag:Team=search", []string{"dev_worker"}},
+		{"name~'^dev_(api|worker)$'", []string{"dev_api", "dev_worker"}},
+		{`name~"_api$"`, []string{"dev_api", "prod_api"}},
+		{"tag:Team", []string{"dev_api", "dev_worker"}},
+		{"trust:service=lambda.*", []string{"dev_api"}},
+		{"trust:aws", []string{"dev_worker"}},
+		{"trust:federated", []string{"prod_api"}},
+		{"created<2024-01-01", []string{"dev_worker"}},
+		{"created<=2024-01-01", []string{"dev_api", "dev_worker"}},
+		{"created>=2024-01-01", []string{"dev_api", "prod_api"}},
+		{"created>2024-01-01", []string{"prod_api"}},
+		{"age<1w", []string{"prod_api"}},
+		{"age>365d", []string{"dev_api", "dev_worker"}},
+		{"lastused<30d", []string{"dev_api"}},
+		{"lastused>90d", []string{"dev_worker", "prod_api"}},
+		{"lastused:never", []string{"dev_worker"}},
+	}
+
+	for _, tt := range tests {
+		selector, err := NewRoleSelector(tt.expression, nil, nil)
+		if err != nil {
+			t.Errorf("%q: %v", tt.expression, err)
+			continue
+		}
+		selector.Now = selectorNow
+
+		if got := selectedNames(selector); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q selected %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestRoleSelectorIncludeExclude(t *testing.T) {
+	selector, err := NewRoleSelector("", []string{"dev_*", "tag:Team=nobody"}, []string{"tag:Team=search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selector.Now = selectorNow
+
+	if got := selectedNames(selector); !reflect.DeepEqual(got, []string{"dev_api"}) {
+		t.Errorf("selected %v, want [dev_api]", got)
+	}
+}
+
+func TestRoleSelectorRequireNameContaining(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"API", []string{"dev_api", "prod_api"}},
+		{"v_w", []string{"dev_worker"}},
+		// Glob characters match literally
+		{"dev_*", nil},
+	}
+
+	for _, tt := range tests {
+		selector, err := NewRoleSelector("", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		selector.RequireNameContaining(tt.text)
+
+		if got := selectedNames(selector); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q selected %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRoleSelectorErrors(t *testing.T) {
+	for _, expression := range []string{
+		"dev_* and",
+		"or dev_*",
+		"(dev_*",
+		"dev_*)",
+		"'dev_*",
+		"name~'('",
+		"tag:",
+		"tag:=payments",
+		"trust:robot",
+		"created<yesterday",
+		"age>90x",
+		"lastused>soon",
+		"color:blue",
+	} {
+		if _, err := NewRoleSelector(expression, nil, nil); err == nil {
+			t.Errorf("%q: expected an error", expression)
+		}
+	}
+}
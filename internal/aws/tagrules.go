@@ -0,0 +1,216 @@
+// internal/aws/tagrules.go - Tag transformation rules for cloned roles
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Tag rule kinds, applied in this order
+const (
+	TagRuleAllow   = "allow"
+	TagRuleDelete  = "delete"
+	TagRuleRename  = "rename"
+	TagRuleReplace = "replace"
+	TagRuleSet     = "set"
+)
+
+// TagRuleOrder is the order in which rule kinds are applied
+var TagRuleOrder = []string{TagRuleAllow, TagRuleDelete, TagRuleRename, TagRuleReplace, TagRuleSet}
+
+// TagContext holds the values available to tag and description templates,
+// e.g. {{.SourceRoleArn}}
+type TagContext struct {
+	SourceRole        string
+	SourceRoleArn     string
+	DestRole          string
+	SourceAccount     string
+	DestAccount       string
+	SourceEnvironment string
+	DestEnvironment   string
+	CloneDate         string
+}
+
+// TagRule is a single tag transformation. Key "*" matches every key for
+// delete and replace rules.
+type TagRule struct {
+	Kind string
+	Key  string
+	// NewKey is the target key of a rename rule
+	NewKey string
+	// Pattern is the regex of a replace rule
+	Pattern *regexp.Regexp
+	// Value is the template of a set rule or the replacement of a replace rule
+	Value *template.Template
+}
+
+// TagRules is an ordered set of tag rules
+type TagRules []TagRule
+
+// NewSetRule sets or overrides a tag with a templated value
+func NewSetRule(key, value string) (TagRule, error) {
+	tmpl, err := ParseTagTemplate(key, value)
+	if err != nil {
+		return TagRule{}, err
+	}
+	return TagRule{Kind: TagRuleSet, Key: key, Value: tmpl}, nil
+}
+
+// NewDeleteRule removes a tag
+func NewDeleteRule(key string) TagRule {
+	return TagRule{Kind: TagRuleDelete, Key: key}
+}
+
+// NewRenameRule moves a tag value to a new key
+func NewRenameRule(key, newKey string) TagRule {
+	return TagRule{Kind: TagRuleRename, Key: key, NewKey: newKey}
+}
+
+// NewAllowRule keeps a tag when allowlisting; every key without an allow rule is dropped
+func NewAllowRule(key string) TagRule {
+	return TagRule{Kind: TagRuleAllow, Key: key}
+}
+
+// NewReplaceRule rewrites a tag value with a regex. The replacement may use
+// $1-style groups and templates.
+func NewReplaceRule(key, pattern, replacement string) (TagRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return TagRule{}, fmt.Errorf("invalid regex for tag %s: %v", key, err)
+	}
+	tmpl, err := ParseTagTemplate(key, replacement)
+	if err != nil {
+		return TagRule{}, err
+	}
+	return TagRule{Kind: TagRuleReplace, Key: key, Pattern: re, Value: tmpl}, nil
+}
+
+// ParseReplaceRule parses "KEY=REGEX=>REPLACEMENT"
+func ParseReplaceRule(spec string) (TagRule, error) {
+	key, rest, ok := strings.Cut(spec, "=")
+	if !ok {
+		return TagRule{}, fmt.Errorf("invalid tag replace rule '%s' (use KEY=REGEX=>REPLACEMENT)", spec)
+	}
+	pattern, replacement, ok := strings.Cut(rest, "=>")
+	if !ok {
+		return TagRule{}, fmt.Errorf("invalid tag replace rule '%s' (use KEY=REGEX=>REPLACEMENT)", spec)
+	}
+	return NewReplaceRule(key, pattern, replacement)
+}
+
+// Apply transforms a copy of tags and returns the result
+func (r TagRules) Apply(tags map[string]string, ctx TagContext) (map[string]string, error) {
+	result := make(map[string]string, len(tags))
+	for key, value := range tags {
+		result[key] = value
+	}
+
+	for _, kind := range TagRuleOrder {
+		rules := r.ofKind(kind)
+		if len(rules) == 0 {
+			continue
+		}
+
+		switch kind {
+		case TagRuleAllow:
+			allowed := make(map[string]bool, len(rules))
+			for _, rule := range rules {
+				allowed[rule.Key] = true
+			}
+			for key := range result {
+				if !allowed[key] {
+					delete(result, key)
+				}
+			}
+
+		case TagRuleDelete:
+			for _, rule := range rules {
+				if rule.Key == "*" {
+					result = make(map[string]string)
+					continue
+				}
+				delete(result, rule.Key)
+			}
+
+		case TagRuleRename:
+			for _, rule := range rules {
+				if value, ok := result[rule.Key]; ok {
+					delete(result, rule.Key)
+					result[rule.NewKey] = value
+				}
+			}
+
+		case TagRuleReplace:
+			for _, rule := range rules {
+				replacement, err := RenderTagTemplate(rule.Value, ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, key := range SortedKeys(result) {
+					if rule.Key == "*" || rule.Key == key {
+						result[key] = rule.Pattern.ReplaceAllString(result[key], replacement)
+					}
+				}
+			}
+
+		case TagRuleSet:
+			for _, rule := range rules {
+				value, err := RenderTagTemplate(rule.Value, ctx)
+				if err != nil {
+					return nil, err
+				}
+				result[rule.Key] = value
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Helper function to select rules of one kind, keeping their order
+func (r TagRules) ofKind(kind string) []TagRule {
+	var rules []TagRule
+	for _, rule := range r {
+		if rule.Kind == kind {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ParseTagTemplate parses a tag or description template and checks its fields
+func ParseTagTemplate(key, text string) (*template.Template, error) {
+	tmpl, err := template.New(key).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template for tag %s: %v", key, err)
+	}
+
+	// Catch unknown fields such as {{.SourceArn}} before any role is processed
+	if _, err := RenderTagTemplate(tmpl, TagContext{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// RenderTagTemplate renders a template parsed with ParseTagTemplate
+func RenderTagTemplate(tmpl *template.Template, ctx TagContext) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("failed to render tag %s: %v", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// SortedKeys lists the keys of a map in a stable order
+func SortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,331 @@
+// internal/aws/trust.go - Structured trust policy parsing and classification
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Principal types found in trust policies
+const (
+	PrincipalService       = "Service"
+	PrincipalAWS           = "AWS"
+	PrincipalFederated     = "Federated"
+	PrincipalCanonicalUser = "CanonicalUser"
+	PrincipalWildcard      = "*"
+)
+
+// Assume-role actions recognized in trust policies
+const (
+	ActionAssumeRole                = "sts:AssumeRole"
+	ActionAssumeRoleWithWebIdentity = "sts:AssumeRoleWithWebIdentity"
+	ActionAssumeRoleWithSAML        = "sts:AssumeRoleWithSAML"
+	ActionTagSession                = "sts:TagSession"
+	ActionSetSourceIdentity         = "sts:SetSourceIdentity"
+)
+
+// Principal is a single entity allowed (or denied) by a trust statement
+type Principal struct {
+	Type  string
+	Value string
+}
+
+// Account returns the AWS account ID of an AWS principal, if it has one
+func (p Principal) Account() string {
+	if p.Type != PrincipalAWS {
+		return ""
+	}
+	return accountFromPrincipal(p.Value)
+}
+
+// Condition is a single operator/key pair from a Condition block
+type Condition struct {
+	Operator string
+	Key      string
+	Values   []string
+}
+
+// TrustStatement is one parsed statement of a trust policy
+type TrustStatement struct {
+	Sid          string
+	Effect       string
+	Principals   []Principal
+	NotPrincipal bool
+	Actions      []string
+	Conditions   []Condition
+}
+
+// TrustPolicy is a parsed assume role policy document
+type TrustPolicy struct {
+	Statements []TrustStatement
+}
+
+// ParseTrustPolicy parses an assume role policy document
+func ParseTrustPolicy(document string) (*TrustPolicy, error) {
+	statements, _, err := parseStatements(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trust policy: %v", err)
+	}
+
+	policy := &TrustPolicy{}
+	for _, item := range statements {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		policy.Statements = append(policy.Statements, parseTrustStatement(raw))
+	}
+
+	return policy, nil
+}
+
+// Helper function to parse one decoded statement of a trust policy
+func parseTrustStatement(raw map[string]interface{}) TrustStatement {
+	statement := TrustStatement{
+		Effect:  stringValue(raw["Effect"]),
+		Sid:     stringValue(raw["Sid"]),
+		Actions: toStringSlice(raw["Action"]),
+	}
+
+	principal, found := raw["Principal"]
+	if !found {
+		principal, found = raw["NotPrincipal"]
+		statement.NotPrincipal = found
+	}
+	statement.Principals = parsePrincipals(principal)
+
+	for operator, block := range conditionsOf(raw) {
+		entries, ok := block.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range entries {
+			statement.Conditions = append(statement.Conditions, Condition{
+				Operator: operator,
+				Key:      key,
+				Values:   toStringSlice(value),
+			})
+		}
+	}
+
+	// Keep condition order stable since it comes from a map
+	sort.Slice(statement.Conditions, func(i, j int) bool {
+		a, b := statement.Conditions[i], statement.Conditions[j]
+		if a.Operator != b.Operator {
+			return a.Operator < b.Operator
+		}
+		return a.Key < b.Key
+	})
+
+	return statement
+}
+
+// Helper function to parse a Principal element into a flat list
+func parsePrincipals(value interface{}) []Principal {
+	if s, ok := value.(string); ok && s == "*" {
+		return []Principal{{Type: PrincipalWildcard, Value: "*"}}
+	}
+
+	block, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var principals []Principal
+	for _, principalType := range []string{PrincipalService, PrincipalAWS, PrincipalFederated, PrincipalCanonicalUser} {
+		for _, v := range toStringSlice(block[principalType]) {
+			if principalType == PrincipalAWS && v == "*" {
+				principals = append(principals, Principal{Type: PrincipalWildcard, Value: "*"})
+				continue
+			}
+			principals = append(principals, Principal{Type: principalType, Value: v})
+		}
+	}
+
+	return principals
+}
+
+// AllowStatements returns the statements with an Allow effect and a Principal
+func (p *TrustPolicy) AllowStatements() []TrustStatement {
+	var result []TrustStatement
+	for _, statement := range p.Statements {
+		if statement.Effect == "Allow" && !statement.NotPrincipal {
+			result = append(result, statement)
+		}
+	}
+	return result
+}
+
+// Principals returns the distinct allowed principals of the given type
+func (p *TrustPolicy) Principals(principalType string) []string {
+	seen := make(map[string]bool)
+	var result []string
+
+	for _, statement := range p.AllowStatements() {
+		for _, principal := range statement.Principals {
+			if principal.Type == principalType && !seen[principal.Value] {
+				seen[principal.Value] = true
+				result = append(result, principal.Value)
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Accounts returns the distinct AWS account IDs trusted through AWS principals
+func (p *TrustPolicy) Accounts() []string {
+	seen := make(map[string]bool)
+	var result []string
+
+	for _, value := range p.Principals(PrincipalAWS) {
+		if account := accountFromPrincipal(value); account != "" && !seen[account] {
+			seen[account] = true
+			result = append(result, account)
+		}
+	}
+
+	return result
+}
+
+// AssumeActions returns the distinct actions granted by Allow statements
+func (p *TrustPolicy) AssumeActions() []string {
+	seen := make(map[string]bool)
+	var result []string
+
+	for _, statement := range p.AllowStatements() {
+		for _, action := range statement.Actions {
+			if !seen[action] {
+				seen[action] = true
+				result = append(result, action)
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// HasWildcardPrincipal reports whether any Allow statement trusts "*"
+func (p *TrustPolicy) HasWildcardPrincipal() bool {
+	return len(p.Principals(PrincipalWildcard)) > 0
+}
+
+// AllowsAction reports whether any Allow statement grants the given action
+func (p *TrustPolicy) AllowsAction(action string) bool {
+	for _, statement := range p.AllowStatements() {
+		for _, a := range statement.Actions {
+			if strings.EqualFold(a, action) || a == "*" || strings.EqualFold(a, "sts:*") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Classify returns a short human-readable label for who can assume the role.
+// accountID is the role's own account; pass "" if unknown.
+func (p *TrustPolicy) Classify(accountID string) string {
+	if p.HasWildcardPrincipal() {
+		return "Public (wildcard principal)"
+	}
+
+	var labels []string
+
+	if services := p.Principals(PrincipalService); len(services) > 0 {
+		switch {
+		case len(services) == 1 && services[0] == "ec2.amazonaws.com":
+			labels = append(labels, "EC2 Service Role")
+		case len(services) == 1 && services[0] == "lambda.amazonaws.com":
+			labels = append(labels, "Lambda Service Role")
+		default:
+			names := make([]string, len(services))
+			for i, service := range services {
+				names[i] = strings.TrimSuffix(service, ".amazonaws.com")
+			}
+			labels = append(labels, fmt.Sprintf("Service Role (%s)", strings.Join(names, ", ")))
+		}
+	}
+
+	for _, federated := range p.Principals(PrincipalFederated) {
+		_, providerType, name, ok := ParseProviderArn(federated)
+		switch {
+		case ok && providerType == ProviderTypeSAML:
+			labels = append(labels, "SAML Federation")
+		case ok && IsEKSProvider(name):
+			labels = append(labels, "EKS Service Account (IRSA)")
+		case ok && name == GitHubOIDCHost:
+			labels = append(labels, "GitHub Actions OIDC")
+		case ok:
+			labels = append(labels, "Web Identity (OIDC)")
+		default:
+			labels = append(labels, fmt.Sprintf("Web Identity (%s)", federated))
+		}
+	}
+
+	if accounts := p.Accounts(); len(accounts) > 0 {
+		external := false
+		for _, account := range accounts {
+			if account != accountID {
+				external = true
+			}
+		}
+		if external || accountID == "" {
+			labels = append(labels, "Cross-Account Role")
+		} else {
+			labels = append(labels, "Same-Account Role")
+		}
+	}
+
+	if len(labels) == 0 {
+		return "Custom Trust Policy"
+	}
+
+	return strings.Join(Dedupe(labels), " + ")
+}
+
+// Helper function to extract the account ID from an AWS principal value
+func accountFromPrincipal(value string) string {
+	if len(value) == 12 && strings.Trim(value, "0123456789") == "" {
+		return value
+	}
+
+	parts := strings.SplitN(value, ":", 6)
+	if len(parts) == 6 && parts[0] == "arn" {
+		return parts[4]
+	}
+
+	return ""
+}
+
+// Helper function to read a string field from a decoded policy
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// Contains reports whether value is in values
+func Contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Dedupe removes duplicate strings while keeping order
+func Dedupe(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
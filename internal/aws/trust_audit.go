@@ -0,0 +1,130 @@
+// internal/aws/trust_audit.go - Trust exposure checks built on the trust parser
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Finding severities, ordered from least to most severe
+const (
+	SeverityLow      = "LOW"
+	SeverityMedium   = "MEDIUM"
+	SeverityHigh     = "HIGH"
+	SeverityCritical = "CRITICAL"
+)
+
+// SeverityRank returns a comparable rank for a severity (0 if unknown)
+func SeverityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	}
+	return 0
+}
+
+// Trust audit check identifiers
+const (
+	CheckWildcardPrincipal = "wildcard-principal"
+	CheckExternalAccount   = "external-account"
+	CheckMissingExternalID = "missing-external-id"
+	// CheckUnreadablePolicy marks a trust policy that could not be parsed, so its
+	// exposure is unknown
+	CheckUnreadablePolicy = "unreadable-trust-policy"
+)
+
+// TrustFinding is a single trust exposure found in a role's trust policy
+type TrustFinding struct {
+	Role      string `json:"role"`
+	Severity  string `json:"severity"`
+	Check     string `json:"check"`
+	Principal string `json:"principal"`
+	Message   string `json:"message"`
+}
+
+// AuditTrust checks a parsed trust policy for principals outside the account,
+// unconditioned wildcard principals and third-party accounts without sts:ExternalId.
+// knownAccounts lists accounts that are trusted in addition to accountID.
+func AuditTrust(roleName string, trust *TrustPolicy, accountID string, knownAccounts []string) []TrustFinding {
+	known := map[string]bool{accountID: true}
+	for _, account := range knownAccounts {
+		known[strings.TrimSpace(account)] = true
+	}
+
+	var findings []TrustFinding
+	for _, statement := range trust.AllowStatements() {
+		for _, principal := range statement.Principals {
+			switch principal.Type {
+			case PrincipalWildcard:
+				if len(statement.Conditions) == 0 {
+					findings = append(findings, TrustFinding{
+						Role:      roleName,
+						Severity:  SeverityCritical,
+						Check:     CheckWildcardPrincipal,
+						Principal: principal.Value,
+						Message:   "Any AWS principal can assume this role (no conditions)",
+					})
+				} else {
+					findings = append(findings, TrustFinding{
+						Role:      roleName,
+						Severity:  SeverityMedium,
+						Check:     CheckWildcardPrincipal,
+						Principal: principal.Value,
+						Message:   fmt.Sprintf("Wildcard principal restricted only by conditions: %s", conditionKeys(statement)),
+					})
+				}
+
+			case PrincipalAWS:
+				account := principal.Account()
+				if account == "" || known[account] {
+					continue
+				}
+
+				findings = append(findings, TrustFinding{
+					Role:      roleName,
+					Severity:  SeverityHigh,
+					Check:     CheckExternalAccount,
+					Principal: principal.Value,
+					Message:   fmt.Sprintf("Trusts account %s which is not in the known accounts list", account),
+				})
+
+				if !hasConditionKey(statement, "sts:ExternalId") {
+					findings = append(findings, TrustFinding{
+						Role:      roleName,
+						Severity:  SeverityHigh,
+						Check:     CheckMissingExternalID,
+						Principal: principal.Value,
+						Message:   fmt.Sprintf("Third-party account %s can assume without sts:ExternalId", account),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// Helper function to check whether a statement has a condition on a key
+func hasConditionKey(statement TrustStatement, key string) bool {
+	for _, condition := range statement.Conditions {
+		if strings.EqualFold(condition.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to summarize the condition keys of a statement
+func conditionKeys(statement TrustStatement) string {
+	keys := make([]string, len(statement.Conditions))
+	for i, condition := range statement.Conditions {
+		keys[i] = condition.Key
+	}
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,139 @@
+// internal/journal/journal.go - Machine-readable record of the mutations a run made
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Journal actions. ActionRun is the first line of every journal.
+const (
+	ActionRun           = "run"
+	ActionCreateRole    = "create-role"
+	ActionAttachPolicy  = "attach-role-policy"
+	ActionPutRolePolicy = "put-role-policy"
+	ActionTagRole       = "tag-role"
+	ActionCreatePolicy  = "create-policy"
+	// ActionUpdatePolicy marks an existing policy the run gave a new default version
+	ActionUpdatePolicy   = "update-policy"
+	ActionCreateProvider = "create-identity-provider"
+	// ActionSyncRole marks an existing role the run overwrote in place
+	ActionSyncRole = "sync-role"
+)
+
+// Entry is one line of a journal
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+
+	// Set on the run entry
+	RunID   string `json:"run_id,omitempty"`
+	Profile string `json:"profile,omitempty"`
+	Account string `json:"account,omitempty"`
+	// Options are the clone options of the run, for recomputing its results
+	Options json.RawMessage `json:"options,omitempty"`
+
+	Role        string   `json:"role,omitempty"`
+	PolicyArn   string   `json:"policy_arn,omitempty"`
+	PolicyName  string   `json:"policy_name,omitempty"`
+	ProviderArn string   `json:"provider_arn,omitempty"`
+	TagKeys     []string `json:"tag_keys,omitempty"`
+	// Hash is the normalized document hash of the policy written
+	Hash string `json:"hash,omitempty"`
+}
+
+// Journal appends entries to a JSON Lines file. A nil Journal records nothing,
+// so dry runs can use the same code path.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+	Path string
+}
+
+// Create starts a new journal with its run entry
+func Create(path, runID, profile, account string, options json.RawMessage) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal: %v", err)
+	}
+
+	j := &Journal{file: file, Path: path}
+	if err := j.Record(Entry{Action: ActionRun, RunID: runID, Profile: profile, Account: account, Options: options}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// Record appends an entry and syncs it to disk so a crash cannot lose it
+func (j *Journal) Record(entry Entry) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %v", err)
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %v", err)
+	}
+	return j.file.Sync()
+}
+
+// Close closes the journal file
+func (j *Journal) Close() {
+	if j != nil {
+		j.file.Close()
+	}
+}
+
+// Read loads a journal and returns its run entry and the mutations in order
+func Read(path string) (Entry, []Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Entry{}, nil, fmt.Errorf("failed to open journal: %v", err)
+	}
+	defer file.Close()
+
+	var run Entry
+	var entries []Entry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return Entry{}, nil, fmt.Errorf("failed to parse journal line %d: %v", line, err)
+		}
+
+		if entry.Action == ActionRun {
+			run = entry
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return Entry{}, nil, fmt.Errorf("failed to read journal: %v", err)
+	}
+
+	if run.RunID == "" {
+		return Entry{}, nil, fmt.Errorf("journal %s has no run entry", path)
+	}
+	return run, entries, nil
+}
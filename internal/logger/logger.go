@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 type Logger struct {
 	verbose bool
 	logFile *os.File
+	out     io.Writer
+	quiet   bool
 }
 
 // new logger instance
@@ -28,9 +31,21 @@ func New(verbose bool, logFileName string) (*Logger, error) {
 	return &Logger{
 		verbose: verbose,
 		logFile: logFile,
+		out:     os.Stdout,
 	}, nil
 }
 
+// SetQuiet suppresses info, success, progress, header and separator output, e.g.
+// when stdout carries data. Warnings, errors and debug messages are still written.
+func (l *Logger) SetQuiet(quiet bool) {
+	l.quiet = quiet
+}
+
+// SetOutput sends messages to w instead of stdout, e.g. to stderr when stdout carries data
+func (l *Logger) SetOutput(w io.Writer) {
+	l.out = w
+}
+
 // Close the log file
 func (l *Logger) Close() {
 	if l.logFile != nil {
@@ -40,19 +55,29 @@ func (l *Logger) Close() {
 
 // Info logs informational messages
 func (l *Logger) Info(message string) {
+	if l.quiet {
+		l.writeToFile("INFO", message)
+		return
+	}
+
 	timestamp := time.Now().Format("15:04:05")
 	coloredMessage := color.New(color.FgBlue).Sprintf("[INFO] %s", message)
 
-	fmt.Printf("%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
+	fmt.Fprintf(l.out, "%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
 	l.writeToFile("INFO", message)
 }
 
 // Success logs success messages
 func (l *Logger) Success(message string) {
+	if l.quiet {
+		l.writeToFile("SUCCESS", message)
+		return
+	}
+
 	timestamp := time.Now().Format("15:04:05")
 	coloredMessage := color.New(color.FgGreen).Sprintf("[SUCCESS] %s", message)
 
-	fmt.Printf("%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
+	fmt.Fprintf(l.out, "%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
 	l.writeToFile("SUCCESS", message)
 }
 
@@ -61,7 +86,7 @@ func (l *Logger) Warning(message string) {
 	timestamp := time.Now().Format("15:04:05")
 	coloredMessage := color.New(color.FgYellow).Sprintf("[WARNING] %s", message)
 
-	fmt.Printf("%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
+	fmt.Fprintf(l.out, "%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
 	l.writeToFile("WARNING", message)
 }
 
@@ -70,7 +95,7 @@ func (l *Logger) Error(message string) {
 	timestamp := time.Now().Format("15:04:05")
 	coloredMessage := color.New(color.FgRed).Sprintf("[ERROR] %s", message)
 
-	fmt.Printf("%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
+	fmt.Fprintf(l.out, "%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
 	l.writeToFile("ERROR", message)
 }
 
@@ -83,17 +108,22 @@ func (l *Logger) Debug(message string) {
 	timestamp := time.Now().Format("15:04:05")
 	coloredMessage := color.New(color.FgMagenta).Sprintf("[DEBUG] %s", message)
 
-	fmt.Printf("%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
+	fmt.Fprintf(l.out, "%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
 	l.writeToFile("DEBUG", message)
 }
 
 // Progress shows a progress message with emoji
 func (l *Logger) Progress(step int, total int, message string) {
+	if l.quiet {
+		l.writeToFile("PROGRESS", fmt.Sprintf("[%d/%d] %s", step, total, message))
+		return
+	}
+
 	timestamp := time.Now().Format("15:04:05")
 	progressMsg := fmt.Sprintf("[%d/%d] %s", step, total, message)
 	coloredMessage := color.New(color.FgWhite).Sprint(progressMsg)
 
-	fmt.Printf("%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
+	fmt.Fprintf(l.out, "%s %s\n", color.New(color.FgCyan).Sprint(timestamp), coloredMessage)
 	l.writeToFile("PROGRESS", progressMsg)
 }
 
@@ -108,14 +138,23 @@ func (l *Logger) writeToFile(level, message string) {
 
 // Header prints a formatted header
 func (l *Logger) Header(title string) {
-	fmt.Println()
-	fmt.Println(color.New(color.FgWhite, color.Bold).Sprint("================================"))
-	fmt.Println(color.New(color.FgWhite, color.Bold).Sprint(title))
-	fmt.Println(color.New(color.FgWhite, color.Bold).Sprint("================================"))
+	if l.quiet {
+		l.writeToFile("HEADER", title)
+		return
+	}
+
+	fmt.Fprintln(l.out)
+	fmt.Fprintln(l.out, color.New(color.FgWhite, color.Bold).Sprint("================================"))
+	fmt.Fprintln(l.out, color.New(color.FgWhite, color.Bold).Sprint(title))
+	fmt.Fprintln(l.out, color.New(color.FgWhite, color.Bold).Sprint("================================"))
 	l.writeToFile("HEADER", title)
 }
 
 // Separator prints a visual separator
 func (l *Logger) Separator() {
-	fmt.Println(color.New(color.FgWhite).Sprint("--------------------------------"))
+	if l.quiet {
+		return
+	}
+
+	fmt.Fprintln(l.out, color.New(color.FgWhite).Sprint("--------------------------------"))
 }
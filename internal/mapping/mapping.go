@@ -0,0 +1,315 @@
+// internal/mapping/mapping.go - Explicit source to destination role name mappings
+package mapping
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Row maps one source role to its destination, with optional per-role overrides
+type Row struct {
+	Source      string `yaml:"source"`
+	Destination string `yaml:"destination"`
+
+	// Replacements are find/replace pairs applied after the global pattern to the
+	// trust policy, inline policy names and documents, and tag values
+	Replacements map[string]string `yaml:"replacements"`
+	// Tags are set on the destination role after the tag rules
+	Tags map[string]string `yaml:"tags"`
+
+	// AttachPolicies are extra managed policy ARNs
+	AttachPolicies []string `yaml:"attach_policies"`
+	// DetachPolicies are managed policy ARNs or names not to attach
+	DetachPolicies []string `yaml:"detach_policies"`
+	// InlinePolicies maps destination inline policy names to document files,
+	// relative to the mapping file. Loaded documents replace the paths.
+	InlinePolicies map[string]string `yaml:"inline_policies"`
+	// DropInlinePolicies are source inline policy names not to copy
+	DropInlinePolicies []string `yaml:"drop_inline_policies"`
+
+	// Line is the line (CSV) or entry number (YAML) of the row, for messages
+	Line int `yaml:"-"`
+}
+
+// File is a loaded mapping file with rows in file order
+type File struct {
+	Path string
+	Rows []*Row `yaml:"roles"`
+}
+
+// CSV columns; multi-value cells separate entries with ';' and pairs with '='
+var csvColumns = []string{
+	"source", "destination", "replacements", "tags",
+	"attach_policies", "detach_policies", "inline_policies", "drop_inline_policies",
+}
+
+// Load reads a .csv, .yaml or .yml mapping file and validates its rows
+func Load(path string) (*File, error) {
+	data, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mapping file: %v", err)
+	}
+	defer data.Close()
+
+	var file *File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		file, err = parseCSV(data)
+	case ".yaml", ".yml":
+		file, err = parseYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported mapping file %s (use .csv, .yaml or .yml)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	file.Path = path
+
+	if err := file.loadDocuments(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if err := file.validate(); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Helper function to parse the YAML format
+func parseYAML(r io.Reader) (*File, error) {
+	file := &File{}
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse mapping file: %v", err)
+	}
+
+	for i, row := range file.Rows {
+		if row == nil {
+			return nil, fmt.Errorf("mapping entry %d is empty", i+1)
+		}
+		row.Line = i + 1
+	}
+	return file, nil
+}
+
+// Helper function to parse the CSV format, which needs a header row
+func parseCSV(r io.Reader) (*File, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file: %v", err)
+	}
+	if len(records) == 0 {
+		return &File{}, nil
+	}
+
+	header := records[0]
+	for _, column := range header {
+		if !contains(csvColumns, strings.TrimSpace(column)) {
+			return nil, fmt.Errorf("unknown mapping column %q (use %s)", column, strings.Join(csvColumns, ", "))
+		}
+	}
+
+	file := &File{}
+	for i, record := range records[1:] {
+		row := &Row{Line: i + 2}
+		for c, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+
+			column := strings.TrimSpace(header[c])
+			switch column {
+			case "source":
+				row.Source = cell
+			case "destination":
+				row.Destination = cell
+			case "attach_policies":
+				row.AttachPolicies = splitList(cell)
+			case "detach_policies":
+				row.DetachPolicies = splitList(cell)
+			case "drop_inline_policies":
+				row.DropInlinePolicies = splitList(cell)
+			default:
+				pairs, err := splitPairs(cell)
+				if err != nil {
+					return nil, fmt.Errorf("mapping line %d, column %s: %v", row.Line, column, err)
+				}
+				switch column {
+				case "replacements":
+					row.Replacements = pairs
+				case "tags":
+					row.Tags = pairs
+				case "inline_policies":
+					row.InlinePolicies = pairs
+				}
+			}
+		}
+		file.Rows = append(file.Rows, row)
+	}
+
+	return file, nil
+}
+
+// Helper function to split "a;b" cells
+func splitList(cell string) []string {
+	var values []string
+	for _, value := range strings.Split(cell, ";") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Helper function to split "k=v;k=v" cells
+func splitPairs(cell string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, entry := range splitList(cell) {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("expected KEY=VALUE, got %q", entry)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return pairs, nil
+}
+
+// Helper function to read inline policy documents relative to the mapping file
+func (f *File) loadDocuments(dir string) error {
+	for _, row := range f.Rows {
+		for name, path := range row.InlinePolicies {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			document, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("mapping entry %d: failed to read inline policy %s: %v", row.Line, name, err)
+			}
+			row.InlinePolicies[name] = string(document)
+		}
+	}
+	return nil
+}
+
+// validate checks required fields and duplicate sources and destinations.
+// IAM role names are unique case-insensitively.
+func (f *File) validate() error {
+	if len(f.Rows) == 0 {
+		return fmt.Errorf("mapping file %s has no roles", f.Path)
+	}
+
+	var problems []string
+	sources := make(map[string]int)
+	destinations := make(map[string]int)
+
+	for _, row := range f.Rows {
+		if row.Source == "" || row.Destination == "" {
+			problems = append(problems, fmt.Sprintf("entry %d: source and destination are required", row.Line))
+			continue
+		}
+
+		if first, ok := sources[strings.ToLower(row.Source)]; ok {
+			problems = append(problems, fmt.Sprintf("entry %d: source %s is already mapped in entry %d", row.Line, row.Source, first))
+		} else {
+			sources[strings.ToLower(row.Source)] = row.Line
+		}
+
+		if first, ok := destinations[strings.ToLower(row.Destination)]; ok {
+			problems = append(problems, fmt.Sprintf("entry %d: destination %s is already used in entry %d", row.Line, row.Destination, first))
+		} else {
+			destinations[strings.ToLower(row.Destination)] = row.Line
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid mapping file %s:\n  %s", f.Path, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// Sources returns the source roles in file order
+func (f *File) Sources() []string {
+	sources := make([]string, len(f.Rows))
+	for i, row := range f.Rows {
+		sources[i] = row.Source
+	}
+	return sources
+}
+
+// Row returns the row of a source role, or nil
+func (f *File) Row(source string) *Row {
+	if f == nil {
+		return nil
+	}
+	for _, row := range f.Rows {
+		if row.Source == source {
+			return row
+		}
+	}
+	return nil
+}
+
+// Replace applies the row's replacements, longest match first so that
+// overlapping keys behave predictably. A nil row changes nothing.
+func (r *Row) Replace(value string) string {
+	if r == nil || len(r.Replacements) == 0 {
+		return value
+	}
+
+	keys := make([]string, 0, len(r.Replacements))
+	for key := range r.Replacements {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	pairs := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key, r.Replacements[key])
+	}
+	return strings.NewReplacer(pairs...).Replace(value)
+}
+
+// Detaches reports whether a managed policy ARN is listed in DetachPolicies,
+// by ARN or by policy name
+func (r *Row) Detaches(policyArn string) bool {
+	if r == nil {
+		return false
+	}
+	name := policyArn[strings.LastIndex(policyArn, "/")+1:]
+	return contains(r.DetachPolicies, policyArn) || contains(r.DetachPolicies, name)
+}
+
+// Drops reports whether a source inline policy is listed in DropInlinePolicies
+func (r *Row) Drops(policyName string) bool {
+	return r != nil && contains(r.DropInlinePolicies, policyName)
+}
+
+// Helper function to check list membership
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// internal/picker/fuzzy.go - Fuzzy matching for the picker search box
+package picker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore scores how well query matches text as a case-insensitive subsequence.
+// It returns false when some query character is missing. Consecutive matches and
+// matches at word starts (after _ - / . or a case change) score higher.
+func FuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	q := []rune(strings.ToLower(query))
+	t := []rune(text)
+
+	score := 0
+	qi := 0
+	previous := -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if unicode.ToLower(t[ti]) != q[qi] {
+			continue
+		}
+
+		points := 1
+		if ti == previous+1 {
+			points += 5
+		}
+		if isWordStart(t, ti) {
+			points += 3
+		}
+		score += points
+		previous = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, false
+	}
+
+	// Prefer shorter texts when the match is otherwise equal
+	return score*100 - len(t), true
+}
+
+// Helper function to detect the first character of a word in a role name
+func isWordStart(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch text[i-1] {
+	case '_', '-', '/', '.', ' ', ':':
+		return true
+	}
+	return unicode.IsUpper(text[i]) && unicode.IsLower(text[i-1])
+}
+
+// FuzzyFilter returns the indexes of the labels matching query, best match first.
+// An empty query keeps every label in its original order.
+func FuzzyFilter(query string, labels []string) []int {
+	type match struct {
+		index int
+		score int
+	}
+
+	var matches []match
+	for i, label := range labels {
+		if score, ok := FuzzyScore(query, label); ok {
+			matches = append(matches, match{index: i, score: score})
+		}
+	}
+
+	if query != "" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+	}
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
@@ -0,0 +1,410 @@
+// internal/picker/picker.go - Full-screen terminal multi-select with fuzzy search
+package picker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrCancelled is returned when the user leaves the picker with Esc or Ctrl-C
+var ErrCancelled = errors.New("selection cancelled")
+
+// Item is one selectable entry
+type Item struct {
+	Label string
+	// Preview returns the lines shown for the item under the cursor. It is called
+	// at most once per item, so it may make API calls.
+	Preview func() []string
+}
+
+// IsTerminal reports whether stdin and stdout are both terminals
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Run shows the picker and returns the indexes of the chosen items in their
+// original order. Without an explicit selection, Enter picks the item under the cursor.
+func Run(title string, items []Item) ([]int, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enter raw terminal mode: %v", err)
+	}
+	defer term.Restore(fd, state)
+
+	out := bufio.NewWriter(os.Stdout)
+	// Alternate screen, hidden cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+		out.Flush()
+	}()
+
+	m := newModel(title, items)
+	buf := make([]byte, 256)
+	for {
+		width, height, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		m.render(out, width, height)
+		out.Flush()
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %v", err)
+		}
+
+		done, err := m.handle(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return m.result(), nil
+		}
+	}
+}
+
+// model is the picker state between key presses
+type model struct {
+	title    string
+	items    []Item
+	labels   []string
+	query    []rune
+	visible  []int
+	cursor   int
+	offset   int
+	selected map[int]bool
+	// anchor is the item last toggled, the start of a range selection
+	anchor   int
+	previews map[int][]string
+	message  string
+}
+
+func newModel(title string, items []Item) *model {
+	m := &model{
+		title:    title,
+		items:    items,
+		labels:   make([]string, len(items)),
+		selected: make(map[int]bool),
+		anchor:   -1,
+		previews: make(map[int][]string),
+	}
+	for i, item := range items {
+		m.labels[i] = item.Label
+	}
+	m.refilter()
+	return m
+}
+
+// Helper function to recompute the visible items after the query changed
+func (m *model) refilter() {
+	m.visible = FuzzyFilter(string(m.query), m.labels)
+	m.cursor = 0
+	m.offset = 0
+}
+
+// Helper function to move the cursor, clamped to the visible items
+func (m *model) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// Helper function to toggle the item under the cursor
+func (m *model) toggle() {
+	if len(m.visible) == 0 {
+		return
+	}
+	index := m.visible[m.cursor]
+	m.selected[index] = !m.selected[index]
+	if !m.selected[index] {
+		delete(m.selected, index)
+	}
+	m.anchor = index
+}
+
+// Helper function to select every visible item between the anchor and the cursor
+func (m *model) selectRange() {
+	if len(m.visible) == 0 {
+		return
+	}
+
+	from := -1
+	for pos, index := range m.visible {
+		if index == m.anchor {
+			from = pos
+		}
+	}
+	if from < 0 {
+		m.message = "Select a first item with space, then move and press Ctrl-R"
+		return
+	}
+
+	to := m.cursor
+	if from > to {
+		from, to = to, from
+	}
+	for pos := from; pos <= to; pos++ {
+		m.selected[m.visible[pos]] = true
+	}
+	m.message = fmt.Sprintf("Selected %d items", to-from+1)
+}
+
+// Helper function to select all visible items, or clear them if all are selected
+func (m *model) toggleAll() {
+	all := true
+	for _, index := range m.visible {
+		if !m.selected[index] {
+			all = false
+			break
+		}
+	}
+	for _, index := range m.visible {
+		if all {
+			delete(m.selected, index)
+		} else {
+			m.selected[index] = true
+		}
+	}
+}
+
+// handle applies one read of keyboard input and reports whether the user confirmed
+func (m *model) handle(input []byte) (bool, error) {
+	m.message = ""
+
+	for i := 0; i < len(input); i++ {
+		b := input[i]
+
+		switch {
+		case b == 0x1b:
+			if i+1 >= len(input) {
+				return false, ErrCancelled
+			}
+			// CSI or SS3 sequence: ESC [ params final or ESC O final
+			if input[i+1] != '[' && input[i+1] != 'O' {
+				return false, ErrCancelled
+			}
+			j := i + 2
+			for j < len(input) && (input[j] < 0x40 || input[j] > 0x7e) {
+				j++
+			}
+			if j >= len(input) {
+				return false, nil
+			}
+			m.handleSequence(string(input[i+2:j]), input[j])
+			i = j
+
+		case b == 3: // Ctrl-C
+			return false, ErrCancelled
+
+		case b == '\r' || b == '\n':
+			if len(m.selected) == 0 && len(m.visible) == 0 {
+				m.message = "Nothing to select"
+				continue
+			}
+			return true, nil
+
+		case b == 127 || b == 8: // Backspace
+			if len(m.query) > 0 {
+				m.query = m.query[:len(m.query)-1]
+				m.refilter()
+			}
+
+		case b == 21: // Ctrl-U
+			m.query = nil
+			m.refilter()
+
+		case b == 1: // Ctrl-A
+			m.toggleAll()
+
+		case b == 18: // Ctrl-R
+			m.selectRange()
+
+		case b == ' ':
+			m.toggle()
+
+		case b == '\t':
+			m.toggle()
+			m.move(1)
+
+		case b == 16: // Ctrl-P
+			m.move(-1)
+
+		case b == 14: // Ctrl-N
+			m.move(1)
+
+		case b >= 0x20:
+			r, size := utf8.DecodeRune(input[i:])
+			m.query = append(m.query, r)
+			m.refilter()
+			i += size - 1
+		}
+	}
+
+	return false, nil
+}
+
+// Helper function to handle arrow and paging keys
+func (m *model) handleSequence(params string, final byte) {
+	page := 10
+	switch {
+	case final == 'A':
+		m.move(-1)
+	case final == 'B':
+		m.move(1)
+	case final == 'H' || (final == '~' && (params == "1" || params == "7")):
+		m.move(-len(m.visible))
+	case final == 'F' || (final == '~' && (params == "4" || params == "8")):
+		m.move(len(m.visible))
+	case final == '~' && params == "5":
+		m.move(-page)
+	case final == '~' && params == "6":
+		m.move(page)
+	}
+}
+
+// result returns the chosen item indexes in their original order
+func (m *model) result() []int {
+	if len(m.selected) == 0 {
+		return []int{m.visible[m.cursor]}
+	}
+
+	indexes := make([]int, 0, len(m.selected))
+	for index := range m.selected {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Helper function to get, and cache, the preview of an item
+func (m *model) preview(index int) []string {
+	if lines, ok := m.previews[index]; ok {
+		return lines
+	}
+	var lines []string
+	if m.items[index].Preview != nil {
+		lines = m.items[index].Preview()
+	}
+	m.previews[index] = lines
+	return lines
+}
+
+// render draws the whole screen. Wide terminals get the preview to the right of
+// the list, narrow ones below it.
+func (m *model) render(out io.Writer, width, height int) {
+	var lines []string
+
+	lines = append(lines, fmt.Sprintf("\x1b[1m%s\x1b[0m  %d/%d shown, %d selected",
+		m.title, len(m.visible), len(m.items), len(m.selected)))
+	lines = append(lines, "> "+string(m.query)+"▏")
+	lines = append(lines, strings.Repeat("─", width))
+
+	var previewLines []string
+	if len(m.visible) > 0 {
+		previewLines = m.preview(m.visible[m.cursor])
+	}
+
+	footer := "↑/↓ move  space/tab select  ^R range  ^A all  ^U clear  enter confirm  esc cancel"
+	if m.message != "" {
+		footer = m.message
+	}
+
+	sideBySide := width >= 100
+	rows := height - len(lines) - 1
+	listWidth := width
+	if sideBySide {
+		listWidth = width * 55 / 100
+	} else {
+		previewRows := len(previewLines) + 1
+		if previewRows > rows/2 {
+			previewRows = rows / 2
+		}
+		rows -= previewRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+
+	// Keep the cursor on screen
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+
+	for row := 0; row < rows; row++ {
+		pos := m.offset + row
+		line := ""
+		if pos < len(m.visible) {
+			index := m.visible[pos]
+			marker := "○"
+			if m.selected[index] {
+				marker = "●"
+			}
+			line = fit(fmt.Sprintf("%s %s", marker, m.labels[index]), listWidth)
+			if pos == m.cursor {
+				line = "\x1b[7m" + line + "\x1b[0m"
+			}
+		} else {
+			line = fit("", listWidth)
+		}
+
+		if sideBySide {
+			previewLine := ""
+			if row < len(previewLines) {
+				previewLine = previewLines[row]
+			}
+			line += " │ " + fit(previewLine, width-listWidth-3)
+		}
+		lines = append(lines, line)
+	}
+
+	if !sideBySide {
+		lines = append(lines, strings.Repeat("─", width))
+		for i := 0; i < len(previewLines) && len(lines) < height-1; i++ {
+			lines = append(lines, fit(previewLines[i], width))
+		}
+	}
+
+	lines = append(lines, "\x1b[2m"+fit(footer, width)+"\x1b[0m")
+
+	fmt.Fprint(out, "\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			fmt.Fprint(out, "\r\n")
+		}
+		fmt.Fprint(out, line, "\x1b[K")
+	}
+	fmt.Fprint(out, "\x1b[J")
+}
+
+// Helper function to truncate or pad text to an exact number of columns
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	if len(runes) > width {
+		if width == 1 {
+			return "…"
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}